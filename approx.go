@@ -0,0 +1,102 @@
+package iterator
+
+import (
+	"hash/maphash"
+	"math"
+	"math/bits"
+
+	"golang.org/x/exp/constraints"
+)
+
+var hashSeed = maphash.MakeSeed()
+
+// HashString is a hash function for strings to use with the approximate modifiers.
+// Its values differ between runs of the program.
+func HashString(s string) uint64 {
+	return maphash.String(hashSeed, s)
+}
+
+// HashInteger is a hash function for integers to use with the approximate modifiers
+func HashInteger[T constraints.Integer](v T) uint64 {
+	return mix64(uint64(v))
+}
+
+// mix64 is the splitmix64 finalizer, it spreads the bits of x over the whole result
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// hyperLogLogPrecision gives 2^14 registers, a standard error of about 0.8%
+const hyperLogLogPrecision = 14
+
+// CountDistinctApprox estimates the number of distinct items using HyperLogLog in constant memory.
+// hashFn must spread items evenly over all 64 bits.
+func CountDistinctApprox[T any](iter Iterator[T], hashFn func(T) uint64) (int, error) {
+	const p = hyperLogLogPrecision
+	registers := make([]uint8, 1<<p)
+
+	_, err := Iterate(iter, func(_ int, item T) (bool, error) {
+		hash := hashFn(item)
+		index := hash >> (64 - p)
+		rank := uint8(bits.LeadingZeros64(hash<<p|1<<(p-1))) + 1
+		registers[index] = max(registers[index], rank)
+		return true, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	m := float64(len(registers))
+	var sum float64
+	var zeros int
+	for _, register := range registers {
+		sum += math.Ldexp(1, -int(register))
+		if register == 0 {
+			zeros++
+		}
+	}
+
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// linear counting is more accurate for small cardinalities
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return int(math.Round(estimate)), nil
+}
+
+// DistinctApprox returns a modifier that skips duplicate items using a Bloom filter sized for
+// expectedItems with the given falsePositiveRate. Memory does not grow with the number of items,
+// but a fraction of the unique items, growing past expectedItems, is skipped as well.
+func DistinctApprox[T any](expectedItems int, falsePositiveRate float64, hashFn func(T) uint64) Modifier[T, T] {
+	if expectedItems < 1 || falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		panic("DistinctApprox: expectedItems must be positive and falsePositiveRate between zero and one")
+	}
+
+	n := float64(expectedItems)
+	size := uint64(math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	hashes := max(1, int(math.Round(float64(size)/n*math.Ln2)))
+
+	return func(iter Iterator[T]) Iterator[T] {
+		filter := make([]uint64, (size+63)/64)
+		return RemoveFunc(func(_ int, item T) (bool, error) {
+			// double hashing derives all positions from two hashes
+			h1 := hashFn(item)
+			h2 := mix64(h1) | 1
+			seen := true
+			for i := 0; i < hashes; i++ {
+				pos := (h1 + uint64(i)*h2) % size
+				word, bit := pos/64, uint64(1)<<(pos%64)
+				if filter[word]&bit == 0 {
+					seen = false
+					filter[word] |= bit
+				}
+			}
+			return seen, nil
+		})(iter)
+	}
+}
@@ -0,0 +1,46 @@
+package iterator
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountDistinctApprox(t *testing.T) {
+	count, err := CountDistinctApprox(Map(func(i int, item int) (int, error) {
+		return item % 100000, nil
+	})(Range(0, 299999, 1)), HashInteger[int])
+	require.NoError(t, err)
+	assert.InEpsilon(t, 100000, count, 0.03)
+
+	count, err = CountDistinctApprox(FromSlice([]string{"a", "b", "a", "c"}), HashString)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	count, err = CountDistinctApprox(Empty[string](), HashString)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestDistinctApprox(t *testing.T) {
+	items := make([]string, 0, 30000)
+	for i := 0; i < 30000; i++ {
+		items = append(items, strconv.Itoa(i%10000))
+	}
+
+	distinct, err := ToSlice(DistinctApprox(10000, 0.01, HashString)(FromSlice(items)))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(distinct), 10000)
+	assert.Greater(t, len(distinct), 9800)
+
+	seen := make(map[string]bool)
+	for _, item := range distinct {
+		assert.False(t, seen[item])
+		seen[item] = true
+	}
+
+	checkIteratorEqual(t, DistinctApprox(10, 0.01, HashInteger[int])(FromSlice([]int{1, 2, 1, 3, 2})), []int{1, 2, 3})
+	assert.Panics(t, func() { DistinctApprox(0, 0.01, HashString) })
+}
@@ -0,0 +1,243 @@
+package iterator
+
+type combinatoricsConfig struct {
+	reuseSlice bool
+}
+
+// CombinatoricsOption configures the combinatorics generators
+type CombinatoricsOption func(*combinatoricsConfig)
+
+// ReuseSlice makes the generators emit the same slice each time, overwriting its items.
+// Items must then be copied if they are kept beyond the next call to Next.
+func ReuseSlice() CombinatoricsOption {
+	return func(c *combinatoricsConfig) {
+		c.reuseSlice = true
+	}
+}
+
+func newCombinatoricsConfig(opts []CombinatoricsOption) *combinatoricsConfig {
+	c := &combinatoricsConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CartesianProduct returns an iterator over all slices taking one item from each of iters in order.
+// The iterators are read once when the first item is requested.
+func CartesianProduct[T any](iters []Iterator[T], opts ...CombinatoricsOption) Iterator[[]T] {
+	var pools [][]T
+	return combinatorics(
+		func() error {
+			pools = make([][]T, len(iters))
+			for i := range iters {
+				if err := drain(iters[i], func(_ int, item T) error {
+					pools[i] = append(pools[i], item)
+					return nil
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		func(pos int) []T { return pools[pos] },
+		func(indices []int) ([]int, bool) {
+			if indices == nil {
+				for _, pool := range pools {
+					if len(pool) == 0 {
+						return nil, false
+					}
+				}
+				return make([]int, len(pools)), true
+			}
+			for i := len(indices) - 1; i >= 0; i-- {
+				indices[i]++
+				if indices[i] < len(pools[i]) {
+					return indices, true
+				}
+				indices[i] = 0
+			}
+			return nil, false
+		},
+		closeAll(iters),
+		newCombinatoricsConfig(opts),
+	)
+}
+
+// Permutations returns a modifier that emits all ordered selections of k items
+func Permutations[T any](k int, opts ...CombinatoricsOption) Modifier[T, []T] {
+	return poolCombinatorics[T](opts, func(n int, indices []int) ([]int, bool) {
+		if indices == nil {
+			if k < 0 || k > n {
+				return nil, false
+			}
+			indices = make([]int, k)
+			for i := range indices {
+				indices[i] = i
+			}
+			return indices, true
+		}
+
+		used := make([]bool, n)
+		for _, index := range indices {
+			used[index] = true
+		}
+		for i := len(indices) - 1; i >= 0; i-- {
+			used[indices[i]] = false
+			next := indices[i] + 1
+			for next < n && used[next] {
+				next++
+			}
+			if next == n {
+				continue
+			}
+			indices[i] = next
+			used[next] = true
+
+			// fill the rest with the smallest unused indices
+			free := 0
+			for j := i + 1; j < len(indices); j++ {
+				for used[free] {
+					free++
+				}
+				indices[j] = free
+				used[free] = true
+			}
+			return indices, true
+		}
+		return nil, false
+	})
+}
+
+// Combinations returns a modifier that emits all selections of k items keeping their order
+func Combinations[T any](k int, opts ...CombinatoricsOption) Modifier[T, []T] {
+	return poolCombinatorics[T](opts, func(n int, indices []int) ([]int, bool) {
+		if indices == nil {
+			if k < 0 || k > n {
+				return nil, false
+			}
+			indices = make([]int, k)
+			for i := range indices {
+				indices[i] = i
+			}
+			return indices, true
+		}
+
+		for i := len(indices) - 1; i >= 0; i-- {
+			if indices[i] < n-len(indices)+i {
+				indices[i]++
+				for j := i + 1; j < len(indices); j++ {
+					indices[j] = indices[j-1] + 1
+				}
+				return indices, true
+			}
+		}
+		return nil, false
+	})
+}
+
+// CombinationsWithReplacement is like Combinations but allows items to be selected more than once
+func CombinationsWithReplacement[T any](k int, opts ...CombinatoricsOption) Modifier[T, []T] {
+	return poolCombinatorics[T](opts, func(n int, indices []int) ([]int, bool) {
+		if indices == nil {
+			if k < 0 || (n == 0 && k > 0) {
+				return nil, false
+			}
+			return make([]int, k), true
+		}
+
+		for i := len(indices) - 1; i >= 0; i-- {
+			if indices[i] < n-1 {
+				indices[i]++
+				for j := i + 1; j < len(indices); j++ {
+					indices[j] = indices[i]
+				}
+				return indices, true
+			}
+		}
+		return nil, false
+	})
+}
+
+// PowerSet returns a modifier that emits all subsets of the items keeping their order,
+// starting with the empty one.
+func PowerSet[T any](opts ...CombinatoricsOption) Modifier[T, []T] {
+	return poolCombinatorics[T](opts, func(n int, indices []int) ([]int, bool) {
+		if indices == nil {
+			return []int{}, true
+		}
+
+		if len(indices) > 0 && indices[len(indices)-1] < n-1 {
+			return append(indices, indices[len(indices)-1]+1), true
+		}
+		if len(indices) == 0 {
+			if n == 0 {
+				return nil, false
+			}
+			return append(indices, 0), true
+		}
+
+		indices = indices[:len(indices)-1]
+		if len(indices) == 0 {
+			return nil, false
+		}
+		indices[len(indices)-1]++
+		return indices, true
+	})
+}
+
+// poolCombinatorics reads the source once and emits the items at the indices returned by advance,
+// which is given nil on the first call.
+func poolCombinatorics[T any](opts []CombinatoricsOption, advance func(n int, indices []int) ([]int, bool)) Modifier[T, []T] {
+	return func(iter Iterator[T]) Iterator[[]T] {
+		var pool []T
+		return combinatorics(
+			func() error {
+				return drain(iter, func(_ int, item T) error {
+					pool = append(pool, item)
+					return nil
+				})
+			},
+			func(int) []T { return pool },
+			func(indices []int) ([]int, bool) {
+				return advance(len(pool), indices)
+			},
+			iter.Close,
+			newCombinatoricsConfig(opts),
+		)
+	}
+}
+
+func combinatorics[T any](load func() error, pool func(pos int) []T, advance func(indices []int) ([]int, bool), close func() error, config *combinatoricsConfig) Iterator[[]T] {
+	var loaded, finished bool
+	var indices []int
+	var items []T
+
+	return OnClose(FromFunc(func() ([]T, bool, error) {
+		if finished {
+			return nil, false, nil
+		}
+		if !loaded {
+			loaded = true
+			if err := load(); err != nil {
+				return nil, false, err
+			}
+		}
+
+		var ok bool
+		indices, ok = advance(indices)
+		if !ok {
+			finished = true
+			return nil, false, nil
+		}
+
+		if !config.reuseSlice || cap(items) < len(indices) {
+			items = make([]T, len(indices))
+		}
+		items = items[:len(indices)]
+		for pos, index := range indices {
+			items[pos] = pool(pos)[index]
+		}
+		return items, true, nil
+	}), close)
+}
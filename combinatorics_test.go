@@ -0,0 +1,80 @@
+package iterator
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCartesianProduct(t *testing.T) {
+	checkIteratorSliceEqual(t, CartesianProduct([]Iterator[int]{FromSlice([]int{1, 2}), FromSlice([]int{3, 4, 5})}), [][]int{
+		{1, 3}, {1, 4}, {1, 5}, {2, 3}, {2, 4}, {2, 5},
+	})
+	checkIteratorSliceEqual(t, CartesianProduct([]Iterator[int]{FromSlice([]int{1, 2}), Empty[int]()}), [][]int{})
+	checkIteratorSliceEqual(t, CartesianProduct[int](nil), [][]int{{}})
+}
+
+func TestPermutations(t *testing.T) {
+	checkIteratorSliceEqual(t, Permutations[int](2)(FromSlice([]int{1, 2, 3})), [][]int{
+		{1, 2}, {1, 3}, {2, 1}, {2, 3}, {3, 1}, {3, 2},
+	})
+	checkIteratorSliceEqual(t, Permutations[int](3)(FromSlice([]int{1, 2, 3})), [][]int{
+		{1, 2, 3}, {1, 3, 2}, {2, 1, 3}, {2, 3, 1}, {3, 1, 2}, {3, 2, 1},
+	})
+	checkIteratorSliceEqual(t, Permutations[int](4)(FromSlice([]int{1, 2, 3})), [][]int{})
+	checkIteratorSliceEqual(t, Permutations[int](0)(FromSlice([]int{1, 2, 3})), [][]int{{}})
+}
+
+func TestCombinations(t *testing.T) {
+	checkIteratorSliceEqual(t, Combinations[int](2)(FromSlice([]int{1, 2, 3, 4})), [][]int{
+		{1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4},
+	})
+	checkIteratorSliceEqual(t, Combinations[int](3)(FromSlice([]int{1, 2})), [][]int{})
+	checkIteratorSliceEqual(t, CombinationsWithReplacement[int](2)(FromSlice([]int{1, 2, 3})), [][]int{
+		{1, 1}, {1, 2}, {1, 3}, {2, 2}, {2, 3}, {3, 3},
+	})
+	checkIteratorSliceEqual(t, CombinationsWithReplacement[int](1)(Empty[int]()), [][]int{})
+}
+
+func TestPowerSet(t *testing.T) {
+	checkIteratorSliceEqual(t, PowerSet[int]()(FromSlice([]int{1, 2, 3})), [][]int{
+		{}, {1}, {1, 2}, {1, 2, 3}, {1, 3}, {2}, {2, 3}, {3},
+	})
+	checkIteratorSliceEqual(t, PowerSet[int]()(Empty[int]()), [][]int{{}})
+}
+
+func TestCombinatoricsReuseSlice(t *testing.T) {
+	iter := Combinations[int](2, ReuseSlice())(FromSlice([]int{1, 2, 3}))
+	require.True(t, iter.Next())
+	first, err := iter.Get()
+	require.NoError(t, err)
+	require.True(t, iter.Next())
+	second, err := iter.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, first)
+	assert.Equal(t, &first[0], &second[0])
+
+	iter = Combinations[int](2)(FromSlice([]int{1, 2, 3}))
+	require.True(t, iter.Next())
+	first, _ = iter.Get()
+	require.True(t, iter.Next())
+	assert.Equal(t, []int{1, 2}, first)
+}
+
+func TestCombinatoricsErrorAndClose(t *testing.T) {
+	sourceErr := errors.New("source error")
+	failing := FromFunc(func() (int, bool, error) { return 0, false, sourceErr })
+	_, err := ToSlice(PowerSet[int]()(failing))
+	assert.ErrorIs(t, err, sourceErr)
+
+	var closed atomic.Int32
+	iters := []Iterator[int]{
+		closeTracker(FromSlice([]int{1, 2}), &closed),
+		closeTracker(FromSlice([]int{3}), &closed),
+	}
+	checkIteratorSliceEqual(t, CartesianProduct(iters), [][]int{{1, 3}, {2, 3}})
+	assert.Equal(t, int32(2), closed.Load())
+}
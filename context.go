@@ -0,0 +1,144 @@
+package iterator
+
+import (
+	"context"
+	"sync"
+)
+
+// WithContext returns a modifier that stops the iterator once ctx is done.
+// Next returns false and Err returns ctx.Err() after cancellation, and the
+// underlying iterator is closed so that any goroutines it started are stopped.
+// While ctx can be cancelled, Next of the underlying iterator runs in a separate
+// goroutine so that a Next blocked in it returns as soon as ctx is done. The
+// underlying iterator is then closed once its pending Next returns.
+//
+// Wrapping the source of Distribute, Mirror or GroupFunc makes the whole
+// pipeline cancellable, their goroutines stop and every consumer gets ctx.Err().
+func WithContext[T any](ctx context.Context) Modifier[T, T] {
+	return func(iter Iterator[T]) Iterator[T] {
+		src := &detachable[T]{iter: iter}
+		results := make(chan bool, 1)
+		var err error
+		var closed bool
+		var closeErr error
+
+		closeIter := func() error {
+			if closed {
+				return closeErr
+			}
+			closed = true
+			if src.detach() {
+				closeErr = src.Close()
+			}
+			return closeErr
+		}
+
+		// stop ends the iterator with ctx.Err() if ctx is done
+		stop := func() bool {
+			if err = ctx.Err(); err != nil {
+				closeIter()
+				return true
+			}
+			return false
+		}
+
+		return &iterator[T]{
+			next: func() bool {
+				if err != nil || stop() {
+					return false
+				}
+
+				var hasMore bool
+				if ctx.Done() == nil {
+					hasMore = src.Next()
+				} else {
+					go func() { results <- src.Next() }()
+					select {
+					case hasMore = <-results:
+					case <-ctx.Done():
+						stop()
+						return false
+					}
+				}
+				if hasMore {
+					return true
+				}
+				// a cancel landing while Next was blocked ends the iterator too
+				if iter.Err() == nil {
+					stop()
+				}
+				return false
+			},
+			get: func() (T, error) {
+				if err != nil {
+					return *new(T), err
+				}
+				return iter.Get()
+			},
+			close: closeIter,
+			err: func() error {
+				if err != nil {
+					return err
+				}
+				return iter.Err()
+			},
+		}
+	}
+}
+
+// detachable guards an iterator whose Next may block indefinitely, like one
+// reading a channel. One goroutine calls Next while another can detach from the
+// iterator without waiting for a pending Next, which then closes the iterator.
+type detachable[T any] struct {
+	iter Iterator[T]
+
+	lock     sync.Mutex
+	inNext   bool
+	detached bool
+	closed   bool
+}
+
+func (d *detachable[T]) Next() bool {
+	d.lock.Lock()
+	if d.detached {
+		d.lock.Unlock()
+		return false
+	}
+	d.inNext = true
+	d.lock.Unlock()
+
+	hasMore := d.iter.Next()
+
+	d.lock.Lock()
+	d.inNext = false
+	detached := d.detached
+	d.lock.Unlock()
+	if detached {
+		d.Close()
+		return false
+	}
+	return hasMore
+}
+
+// detach stops any further Next. It reports whether the caller should close
+// the iterator, otherwise the pending Next closes it once it returns.
+func (d *detachable[T]) detach() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.detached = true
+	return !d.inNext
+}
+
+func (d *detachable[T]) Close() error {
+	d.lock.Lock()
+	if d.closed {
+		d.lock.Unlock()
+		return nil
+	}
+	d.closed = true
+	d.lock.Unlock()
+	return d.iter.Close()
+}
+
+func (d *detachable[T]) Get() (T, error) { return d.iter.Get() }
+func (d *detachable[T]) Err() error      { return d.iter.Err() }
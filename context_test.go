@@ -0,0 +1,199 @@
+package iterator
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func closeTracker[T any](iter Iterator[T], closed *atomic.Int32) Iterator[T] {
+	return OnClose(iter, func() error {
+		closed.Add(1)
+		return nil
+	})
+}
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var closed atomic.Int32
+	iter := WithContext[int](ctx)(closeTracker(Ascending(0, 1), &closed))
+
+	var items []int
+	for iter.Next() {
+		item, err := iter.Get()
+		require.NoError(t, err)
+		items = append(items, item)
+		if item == 2 {
+			cancel()
+		}
+	}
+	assert.Equal(t, []int{0, 1, 2}, items)
+	require.ErrorIs(t, iter.Err(), context.Canceled)
+	assert.EqualValues(t, 1, closed.Load())
+	require.NoError(t, iter.Close())
+	assert.EqualValues(t, 1, closed.Load())
+}
+
+func TestIterateContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := IterateContext(ctx, Range(1, 5, 1), func(_ int, _ int) (bool, error) {
+		return true, nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+
+	items, err := ToSliceContext(context.Background(), Range(1, 3, 1))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, items)
+
+	sum, err := FoldContext(context.Background(), Range(1, 3, 1), 0, func(_ int, item int, sum int) (int, error) {
+		return sum + item, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 6, sum)
+}
+
+func TestToChannelContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var closed atomic.Int32
+	c, stop := ToChannelContext(ctx, closeTracker(Ascending(0, 1), &closed), 0)
+	defer stop()
+
+	for i := 0; i < 3; i++ {
+		item := <-c
+		require.NoError(t, item.Err)
+		assert.Equal(t, i, item.Val)
+	}
+	cancel()
+
+	var err error
+	for item := range c {
+		err = item.Err
+	}
+	require.ErrorIs(t, err, context.Canceled)
+	assert.EqualValues(t, 1, closed.Load())
+}
+
+func TestToChannelStop(t *testing.T) {
+	var closed atomic.Int32
+	c, stop := ToChannel(closeTracker(Ascending(0, 1), &closed), 0)
+	<-c
+	stop()
+	stop()
+	for range c {
+	}
+	assert.EqualValues(t, 1, closed.Load())
+}
+
+func TestMergeClose(t *testing.T) {
+	var closed atomic.Int32
+	merged := Merge(closeTracker(Ascending(0, 1), &closed), closeTracker(Ascending(0, 1), &closed))
+	require.True(t, merged.Next())
+	require.NoError(t, merged.Close())
+	assert.EqualValues(t, 2, closed.Load())
+}
+
+func TestMirrorClose(t *testing.T) {
+	var closed atomic.Int32
+	mirrors := Mirror(closeTracker(Ascending(0, 1), &closed), 2)
+	require.True(t, mirrors[0].Next())
+	require.NoError(t, mirrors[0].Close())
+	assert.EqualValues(t, 0, closed.Load())
+	require.True(t, mirrors[1].Next())
+	require.NoError(t, mirrors[1].Close())
+	assert.EqualValues(t, 1, closed.Load())
+}
+
+func TestGroupFuncClose(t *testing.T) {
+	var closed atomic.Int32
+	groups := Group(closeTracker(FromSlice([]int{1, 2, 1, 3, 2}), &closed))
+	require.True(t, groups.Next())
+	group, err := groups.Get()
+	require.NoError(t, err)
+	require.NoError(t, groups.Close())
+	assert.EqualValues(t, 0, closed.Load())
+
+	require.True(t, group.Next())
+	require.NoError(t, group.Close())
+	assert.EqualValues(t, 1, closed.Load())
+}
+
+func TestWithContextBlocked(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan int)
+	defer close(c)
+	iter := WithContext[int](ctx)(FromChannel(c))
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	returned := make(chan bool)
+	go func() { returned <- iter.Next() }()
+	select {
+	case hasMore := <-returned:
+		assert.False(t, hasMore)
+	case <-time.After(time.Second):
+		require.FailNow(t, "Next did not return after cancel")
+	}
+	require.ErrorIs(t, iter.Err(), context.Canceled)
+	require.NoError(t, iter.Close())
+
+	items, err := ToSliceContext(ctx, FromChannel(c))
+	assert.Empty(t, items)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestMergeCloseBlocked(t *testing.T) {
+	c := make(chan int)
+	defer close(c)
+	merged := Merge(FromChannel(c), Range(1, 3, 1))
+	require.True(t, merged.Next())
+
+	closed := make(chan error)
+	go func() { closed <- merged.Close() }()
+	select {
+	case err := <-closed:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		require.FailNow(t, "Close waited for a source blocked in Next")
+	}
+}
+
+func TestPipelineCancel(t *testing.T) {
+	c := make(chan int)
+	defer close(c)
+
+	checkCancelled := func(t *testing.T, cancel func(), iters ...Iterator[int]) {
+		time.AfterFunc(10*time.Millisecond, cancel)
+		for _, iter := range iters {
+			for iter.Next() {
+			}
+			assert.ErrorIs(t, iter.Err(), context.Canceled)
+			iter.Close()
+		}
+	}
+
+	t.Run("Mirror", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		checkCancelled(t, cancel, Mirror(WithContext[int](ctx)(FromChannel(c)), 2)...)
+	})
+	t.Run("Distribute", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		dists := DistributeBy(2, func(_ int, item int) (int, error) { return item, nil })(WithContext[int](ctx)(FromChannel(c)))
+		iters, err := ToSlice(dists)
+		require.NoError(t, err)
+		checkCancelled(t, cancel, iters...)
+	})
+	t.Run("GroupFunc", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		groups := Group(WithContext[int](ctx)(FromChannel(c)))
+		time.AfterFunc(10*time.Millisecond, cancel)
+		assert.False(t, groups.Next())
+		assert.ErrorIs(t, groups.Err(), context.Canceled)
+		groups.Close()
+	})
+}
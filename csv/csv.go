@@ -15,6 +15,14 @@ type Reader[T ~string] struct {
 	started      bool
 	finished     bool
 
+	recorder   *recorder
+	lenient    bool
+	onRowError func(*RowError)
+	maxErrors  int
+	errCount   int
+	validators []Validator
+	columns    []int
+
 	header []string
 	curr   []T
 	err    error
@@ -29,6 +37,21 @@ func (r *Reader[T]) FixedFieldsCount()          { r.reader.FieldsPerRecord = 0 }
 func (r *Reader[T]) VaryingFieldsCount()        { r.reader.FieldsPerRecord = -1 }
 func (r *Reader[T]) ExpectHeader(header bool)   { r.expectHeader = header }
 
+// Lenient makes the reader skip malformed or invalid rows instead of stopping
+func (r *Reader[T]) Lenient(lenient bool) {
+	r.lenient = lenient
+	r.recorder.enabled = lenient
+}
+
+// OnRowError sets a function called with each row skipped in lenient mode
+func (r *Reader[T]) OnRowError(fn func(*RowError)) { r.onRowError = fn }
+
+// MaxErrors stops a lenient reader with ErrTooManyErrors once more than max rows were skipped
+func (r *Reader[T]) MaxErrors(max int) { r.maxErrors = max }
+
+// Validate sets validators every row has to pass, they need the header to find their columns
+func (r *Reader[T]) Validate(validators ...Validator) { r.validators = validators }
+
 func (r *Reader[T]) Header() ([]string, error) {
 	if !r.expectHeader {
 		return nil, errors.New("csv: not expecting header")
@@ -44,6 +67,7 @@ func (r *Reader[T]) Header() ([]string, error) {
 	}
 
 	header, err := r.reader.Read()
+	r.recorder.take(r.reader.InputOffset())
 	if err != nil {
 		if errors.Is(err, io.EOF) {
 			r.finished = true
@@ -67,15 +91,36 @@ func (r *Reader[T]) Next() bool {
 			return false
 		}
 	}
+	if err := r.resolveColumns(); err != nil {
+		r.err = err
+		return false
+	}
 
-	fields, err := r.reader.Read()
-	if err != nil {
+	var fields []string
+	for {
+		var err error
+		fields, err = r.reader.Read()
 		if errors.Is(err, io.EOF) {
 			r.finished = true
 			return false
 		}
-		r.err = err
-		return false
+		raw := r.recorder.take(r.reader.InputOffset())
+
+		if err == nil {
+			err = r.validate(fields)
+		}
+		if err == nil {
+			break
+		}
+
+		if !r.lenient {
+			r.err = err
+			return false
+		}
+		if err := r.skip(err, raw); err != nil {
+			r.err = err
+			return false
+		}
 	}
 
 	if !r.started {
@@ -144,12 +189,17 @@ func (w *Writer[T]) Write() error {
 }
 
 func Read[T ~string](r io.Reader) *Reader[T] {
-	reader := csv.NewReader(r)
+	rec := &recorder{reader: r}
+	reader := &Reader[T]{
+		reader:    csv.NewReader(rec),
+		recorder:  rec,
+		maxErrors: -1,
+	}
 
 	if closer, ok := r.(io.ReadCloser); ok {
-		return &Reader[T]{reader: reader, closer: closer.Close}
+		reader.closer = closer.Close
 	}
-	return &Reader[T]{reader: reader}
+	return reader
 }
 
 func Write[T ~string](w io.Writer, iter it.Iterator[[]T]) *Writer[T] {
@@ -0,0 +1,147 @@
+package csv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ErrTooManyErrors is returned once a lenient reader skipped more rows than allowed
+var ErrTooManyErrors = errors.New("csv: too many row errors")
+
+// RowError describes a row which could not be parsed or failed validation.
+// Raw is only set in lenient mode.
+type RowError struct {
+	Line int
+	Raw  string
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("csv: line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error { return e.Err }
+
+// Validator checks the value of a column in every row
+type Validator struct {
+	column string
+	check  func(string) error
+}
+
+// Required fails rows with an empty value in column
+func Required(column string) Validator {
+	return Validator{column: column, check: func(value string) error {
+		if value == "" {
+			return fmt.Errorf("column %q is required", column)
+		}
+		return nil
+	}}
+}
+
+// Match fails rows whose value in column does not match re
+func Match(column string, re *regexp.Regexp) Validator {
+	return Validator{column: column, check: func(value string) error {
+		if !re.MatchString(value) {
+			return fmt.Errorf("column %q value %q does not match %s", column, value, re)
+		}
+		return nil
+	}}
+}
+
+// OneOf fails rows whose value in column is not one of values
+func OneOf(column string, values ...string) Validator {
+	return Validator{column: column, check: func(value string) error {
+		if !slices.Contains(values, value) {
+			return fmt.Errorf("column %q value %q is not one of %s", column, value, strings.Join(values, ", "))
+		}
+		return nil
+	}}
+}
+
+// resolveColumns finds the columns of the validators in the header
+func (r *Reader[T]) resolveColumns() error {
+	if len(r.validators) == 0 || r.columns != nil {
+		return nil
+	}
+	if r.header == nil {
+		return errors.New("csv: validators need a header")
+	}
+
+	r.columns = make([]int, len(r.validators))
+	for i, v := range r.validators {
+		r.columns[i] = slices.Index(r.header, v.column)
+		if r.columns[i] < 0 {
+			return fmt.Errorf("csv: unknown column %q", v.column)
+		}
+	}
+	return nil
+}
+
+func (r *Reader[T]) validate(fields []string) error {
+	for i, v := range r.validators {
+		var value string
+		if r.columns[i] < len(fields) {
+			value = fields[r.columns[i]]
+		}
+		if err := v.check(value); err != nil {
+			line, _ := r.reader.FieldPos(0)
+			return &RowError{Line: line, Err: err}
+		}
+	}
+	return nil
+}
+
+// skip reports a skipped row and returns an error once the error budget is exceeded
+func (r *Reader[T]) skip(err error, raw string) error {
+	rowErr, ok := err.(*RowError)
+	if !ok {
+		rowErr = &RowError{Err: err}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rowErr.Line = parseErr.StartLine
+		}
+	}
+	rowErr.Raw = raw
+
+	r.errCount++
+	if r.onRowError != nil {
+		r.onRowError(rowErr)
+	}
+	if r.maxErrors >= 0 && r.errCount > r.maxErrors {
+		return fmt.Errorf("%w: %w", ErrTooManyErrors, rowErr)
+	}
+	return nil
+}
+
+// recorder keeps the input read by the csv reader so the raw text of rows can be recovered
+type recorder struct {
+	reader  io.Reader
+	enabled bool
+	data    []byte
+	base    int64
+}
+
+func (r *recorder) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if r.enabled {
+		r.data = append(r.data, p[:n]...)
+	}
+	return n, err
+}
+
+// take returns the input up to offset which was not taken yet
+func (r *recorder) take(offset int64) string {
+	if !r.enabled || offset < r.base {
+		return ""
+	}
+	end := min(int(offset-r.base), len(r.data))
+	raw := string(r.data[:end])
+	r.data = r.data[end:]
+	r.base = offset
+	return strings.TrimRight(raw, "\r\n")
+}
@@ -0,0 +1,73 @@
+package csv
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wlMalk/iterator"
+)
+
+const messyInput = "id,status\n" +
+	"1,open\n" +
+	"2,\"clo\"sed\n" +
+	"3,closed,extra\n" +
+	",open\n" +
+	"x5,open\n" +
+	"6,pending\n" +
+	"7,closed\n"
+
+func TestReadLenient(t *testing.T) {
+	reader := Read[string](strings.NewReader(messyInput))
+	reader.ExpectHeader(true)
+	reader.Lenient(true)
+	reader.Validate(Required("id"), Match("id", regexp.MustCompile(`^\d+$`)), OneOf("status", "open", "closed"))
+
+	var rowErrs []*RowError
+	reader.OnRowError(func(err *RowError) { rowErrs = append(rowErrs, err) })
+
+	checkIteratorEqual[[]string](t, reader, [][]string{{"1", "open"}, {"7", "closed"}})
+
+	require.Len(t, rowErrs, 5)
+	lines := make([]int, len(rowErrs))
+	raws := make([]string, len(rowErrs))
+	for i, err := range rowErrs {
+		lines[i], raws[i] = err.Line, err.Raw
+	}
+	assert.Equal(t, []int{3, 4, 5, 6, 7}, lines)
+	assert.Equal(t, []string{`2,"clo"sed`, "3,closed,extra", ",open", "x5,open", "6,pending"}, raws)
+	assert.ErrorContains(t, rowErrs[2], "required")
+}
+
+func TestReadLenientMaxErrors(t *testing.T) {
+	reader := Read[string](strings.NewReader(messyInput))
+	reader.ExpectHeader(true)
+	reader.Lenient(true)
+	reader.MaxErrors(1)
+
+	_, err := iterator.ToSlice[[]string](reader)
+	assert.ErrorIs(t, err, ErrTooManyErrors)
+	var rowErr *RowError
+	require.ErrorAs(t, err, &rowErr)
+	assert.Equal(t, 4, rowErr.Line)
+}
+
+func TestReadValidateStrict(t *testing.T) {
+	reader := Read[string](strings.NewReader("id,status\n1,open\n2,done\n"))
+	reader.ExpectHeader(true)
+	reader.Validate(OneOf("status", "open", "closed"))
+
+	_, err := iterator.ToSlice[[]string](reader)
+	var rowErr *RowError
+	require.ErrorAs(t, err, &rowErr)
+	assert.Equal(t, 3, rowErr.Line)
+
+	reader = Read[string](strings.NewReader("id,status\n1,open\n"))
+	reader.ExpectHeader(true)
+	reader.Lenient(true)
+	reader.Validate(Required("name"))
+	_, err = iterator.ToSlice[[]string](reader)
+	assert.ErrorContains(t, err, `unknown column "name"`)
+}
@@ -0,0 +1,291 @@
+package csv
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+
+	it "github.com/wlMalk/iterator"
+)
+
+// FieldError is returned when a cell can not be decoded into its struct field
+type FieldError struct {
+	Row    int
+	Column int
+	Name   string
+	Err    error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("csv: row %d, column %d (%s): %v", e.Row, e.Column, e.Name, e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+type structField struct {
+	index  int
+	name   string
+	layout string
+}
+
+// structFields returns the fields of t mapped to columns, named by their csv tag or their name.
+// Fields tagged with "-" and unexported fields are left out.
+func structFields(t reflect.Type) ([]structField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csv: %s is not a struct", t)
+	}
+
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := f.Tag.Get("csv")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		layout := f.Tag.Get("layout")
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		fields = append(fields, structField{index: i, name: name, layout: layout})
+	}
+	return fields, nil
+}
+
+// StructReader decodes the rows of a csv file with a header into structs.
+// Columns are matched to fields by name, columns without a field are skipped
+// and fields without a column are left at their zero value.
+type StructReader[S any] struct {
+	reader  *Reader[string]
+	columns []*structField
+	curr    S
+	err     error
+}
+
+func (r *StructReader[S]) Delimiter(delim rune)       { r.reader.Delimiter(delim) }
+func (r *StructReader[S]) Comment(comment rune)       { r.reader.Comment(comment) }
+func (r *StructReader[S]) TrimLeadingSpace(trim bool) { r.reader.TrimLeadingSpace(trim) }
+func (r *StructReader[S]) LazyQuotes(lazyQuotes bool) { r.reader.LazyQuotes(lazyQuotes) }
+
+func (r *StructReader[S]) Header() ([]string, error) { return r.reader.Header() }
+
+func (r *StructReader[S]) Next() bool {
+	if r.err != nil {
+		return false
+	}
+
+	if r.columns == nil {
+		header, err := r.reader.Header()
+		if err != nil {
+			r.err = err
+			return false
+		}
+		fields, err := structFields(reflect.TypeFor[S]())
+		if err != nil {
+			r.err = err
+			return false
+		}
+
+		r.columns = make([]*structField, len(header))
+		for i, name := range header {
+			for j := range fields {
+				if fields[j].name == name {
+					r.columns[i] = &fields[j]
+					break
+				}
+			}
+		}
+	}
+
+	if !r.reader.Next() {
+		r.err = r.reader.Err()
+		return false
+	}
+	row, _ := r.reader.Get()
+
+	var curr S
+	value := reflect.ValueOf(&curr).Elem()
+	for i, cell := range row {
+		if i >= len(r.columns) || r.columns[i] == nil {
+			continue
+		}
+		if err := decodeCell(value.Field(r.columns[i].index), cell, r.columns[i].layout); err != nil {
+			line, _ := r.reader.reader.FieldPos(i)
+			r.err = &FieldError{Row: line, Column: i + 1, Name: r.columns[i].name, Err: err}
+			return false
+		}
+	}
+	r.curr = curr
+
+	return true
+}
+
+func (r *StructReader[S]) Get() (S, error) {
+	return r.curr, r.err
+}
+
+func (r *StructReader[S]) Close() error { return r.reader.Close() }
+
+func (r *StructReader[S]) Err() error { return r.err }
+
+func decodeCell(v reflect.Value, cell, layout string) error {
+	if v.Kind() == reflect.Pointer {
+		// empty cells leave nullable fields nil
+		if cell == "" {
+			v.SetZero()
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeCell(elem.Elem(), cell, layout); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	if v.Type() == timeType {
+		t, err := time.Parse(layout, cell)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(cell))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(cell)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(cell, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(cell, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func encodeCell(v reflect.Value, layout string) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		return encodeCell(v.Elem(), layout)
+	}
+
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(layout), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textMarshalerType) {
+		text, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// StructWriter encodes structs into the rows of a csv file with a header derived from their fields
+type StructWriter[S any] struct {
+	writer *Writer[string]
+	iter   it.Iterator[S]
+}
+
+func (w *StructWriter[S]) Delimiter(delim rune) { w.writer.Delimiter(delim) }
+func (w *StructWriter[S]) UseCRLF(useCRLF bool) { w.writer.UseCRLF(useCRLF) }
+
+func (w *StructWriter[S]) Write() error {
+	fields, err := structFields(reflect.TypeFor[S]())
+	if err != nil {
+		w.iter.Close()
+		return err
+	}
+
+	header := make([]string, len(fields))
+	for i := range fields {
+		header[i] = fields[i].name
+	}
+	w.writer.Header(header)
+
+	w.writer.iter = it.Map(func(i int, item S) ([]string, error) {
+		value := reflect.ValueOf(&item).Elem()
+		row := make([]string, len(fields))
+		for j := range fields {
+			cell, err := encodeCell(value.Field(fields[j].index), fields[j].layout)
+			if err != nil {
+				return nil, &FieldError{Row: i + 2, Column: j + 1, Name: fields[j].name, Err: err}
+			}
+			row[j] = cell
+		}
+		return row, nil
+	})(w.iter)
+
+	return w.writer.Write()
+}
+
+// ReadStructs returns a reader decoding the rows of r into structs of type S.
+// Fields are matched to the header by their csv tag, time.Time fields are parsed
+// using their layout tag or time.RFC3339, and empty cells leave pointer fields nil.
+func ReadStructs[S any](r io.Reader) *StructReader[S] {
+	reader := Read[string](r)
+	reader.ExpectHeader(true)
+	return &StructReader[S]{reader: reader}
+}
+
+// WriteStructs returns a writer encoding the structs of iter into rows of w after a header
+func WriteStructs[S any](w io.Writer, iter it.Iterator[S]) *StructWriter[S] {
+	return &StructWriter[S]{
+		writer: Write[string](w, nil),
+		iter:   iter,
+	}
+}
@@ -0,0 +1,92 @@
+package csv
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wlMalk/iterator"
+)
+
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	n, err := strconv.Atoi(strings.TrimPrefix(string(text), "L"))
+	*l = level(n)
+	return err
+}
+
+func (l level) MarshalText() ([]byte, error) {
+	return []byte("L" + strconv.Itoa(int(l))), nil
+}
+
+type record struct {
+	Name    string    `csv:"name"`
+	Age     int       `csv:"age"`
+	Score   float64   `csv:"score"`
+	Active  bool      `csv:"active"`
+	Born    time.Time `csv:"born" layout:"2006-01-02"`
+	Manager *string   `csv:"manager"`
+	Level   level     `csv:"level"`
+	Ignored string    `csv:"-"`
+}
+
+func TestReadStructs(t *testing.T) {
+	input := "name,extra,age,score,active,born,manager,level\n" +
+		"ann,x,31,9.5,true,1993-04-01,bob,L2\n" +
+		"bob,y,45,7,false,1979-12-24,,L3\n"
+
+	manager := "bob"
+	checkIteratorEqual[record](t, ReadStructs[record](strings.NewReader(input)), []record{
+		{Name: "ann", Age: 31, Score: 9.5, Active: true, Born: time.Date(1993, 4, 1, 0, 0, 0, 0, time.UTC), Manager: &manager, Level: 2},
+		{Name: "bob", Age: 45, Score: 7, Born: time.Date(1979, 12, 24, 0, 0, 0, 0, time.UTC), Level: 3},
+	})
+}
+
+func TestReadStructsMissingColumns(t *testing.T) {
+	input := "age,name\n31,ann\n"
+	checkIteratorEqual[record](t, ReadStructs[record](strings.NewReader(input)), []record{{Name: "ann", Age: 31}})
+}
+
+func TestReadStructsNotStruct(t *testing.T) {
+	_, err := iterator.ToSlice[any](ReadStructs[any](strings.NewReader("name\nann\n")))
+	assert.ErrorContains(t, err, "is not a struct")
+
+	err = WriteStructs[any](&bytes.Buffer{}, iterator.FromSlice([]any{1})).Write()
+	assert.ErrorContains(t, err, "is not a struct")
+}
+
+func TestReadStructsError(t *testing.T) {
+	input := "name,age\nann,31\nbob,old\n"
+	_, err := iterator.ToSlice[record](ReadStructs[record](strings.NewReader(input)))
+
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, 3, fieldErr.Row)
+	assert.Equal(t, 2, fieldErr.Column)
+	assert.Equal(t, "age", fieldErr.Name)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+}
+
+func TestWriteStructs(t *testing.T) {
+	manager := "bob"
+	records := iterator.FromSlice([]record{
+		{Name: "ann", Age: 31, Score: 9.5, Active: true, Born: time.Date(1993, 4, 1, 0, 0, 0, 0, time.UTC), Manager: &manager, Level: 2},
+		{Name: "bob", Age: 45, Score: 7, Born: time.Date(1979, 12, 24, 0, 0, 0, 0, time.UTC), Level: 3, Ignored: "x"},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteStructs(&buf, records).Write())
+	assert.Equal(t, "name,age,score,active,born,manager,level\n"+
+		"ann,31,9.5,true,1993-04-01,bob,L2\n"+
+		"bob,45,7,false,1979-12-24,,L3\n", buf.String())
+
+	roundTrip, err := iterator.ToSlice[record](ReadStructs[record](&buf))
+	require.NoError(t, err)
+	assert.Len(t, roundTrip, 2)
+	assert.Equal(t, "bob", *roundTrip[0].Manager)
+}
@@ -1,136 +1,337 @@
 package iterator
 
 import (
+	"context"
 	"sync"
 )
 
-// Distribute returns an iterator of the specified number of iterators.
-// Each iterator contains a subset of the items and all of them can be
-// consumed in parallel.
+// Distribute returns an iterator of iterators. Each iterator contains a subset
+// of the items and all of them can be consumed in parallel.
+// Items are sent to the iterators in round robin, starting once the outer iterator
+// is closed or any of its iterators is advanced. Iterators created after that
+// join the rotation from then on.
+// buffer is the number of items each iterator can hold before its consumer reads them.
 func Distribute[T any](buffer int) Modifier[T, Iterator[T]] {
 	return func(iter Iterator[T]) Iterator[Iterator[T]] {
-		return newDistributeIterator(iter, buffer)
+		return newDistributeIterator(iter, buffer, 0, nil, false)
+	}
+}
+
+// DistributeBy returns an iterator of n iterators. Each iterator contains the items
+// for a subset of the keys returned by fn, so all items with the same key
+// are consumed by the same iterator.
+// Keys are assigned to the iterators in round robin as they are first seen.
+// Items for an iterator that has been closed are dropped.
+func DistributeBy[T any, K comparable](n int, fn func(int, T) (K, error)) Modifier[T, Iterator[T]] {
+	if n <= 0 {
+		panic("DistributeBy: n must be greater than zero")
+	}
+	return func(iter Iterator[T]) Iterator[Iterator[T]] {
+		keys := make(map[K]int)
+		return newDistributeIterator(iter, 0, n, func(i int, item T) (int, error) {
+			key, err := fn(i, item)
+			if err != nil {
+				return 0, err
+			}
+			target, ok := keys[key]
+			if !ok {
+				target = len(keys) % n
+				keys[key] = target
+			}
+			return target, nil
+		}, false)
+	}
+}
+
+// DistributeBalanced is like Distribute but each item is consumed by whichever
+// iterator is ready first instead of strict round robin.
+func DistributeBalanced[T any](buffer int) Modifier[T, Iterator[T]] {
+	return func(iter Iterator[T]) Iterator[Iterator[T]] {
+		return newDistributeIterator(iter, buffer, 0, nil, true)
 	}
 }
 
 type distributeIterator[T any] struct {
-	iter      Iterator[T]
-	wg        sync.WaitGroup
-	startChan chan struct{}
-	closeChan chan int
-	buffer    int
-	chans     []chan ValErr[T]
+	source Iterator[T]
+	route  func(int, T) (int, error)
+	buffer int
+	shared chan T
+
+	lock      sync.Mutex
+	subs      []*distributedIterator[T]
 	curr      Iterator[T]
-	count     int
-	done      bool
-	err       error
-	lock      sync.RWMutex
+	created   int
+	turn      int
+	sealed    bool
+	started   bool
+	openCount int
+
+	startChan chan struct{}
+	ctx       context.Context
+	cancel    context.CancelFunc
+	finished  chan struct{}
+
+	err      error
+	closeErr error
+}
+
+type distributedIterator[T any] struct {
+	parent *distributeIterator[T]
+	items  chan T
+	closed chan struct{}
+	once   sync.Once
+
+	curr     T
+	err      error
+	finished bool
 }
 
-func newDistributeIterator[T any](iter Iterator[T], buffer int) *distributeIterator[T] {
+func newDistributeIterator[T any](source Iterator[T], buffer int, size int, route func(int, T) (int, error), balanced bool) *distributeIterator[T] {
+	ctx, cancel := context.WithCancel(context.Background())
 	d := &distributeIterator[T]{
-		iter:      iter,
+		source:    source,
+		route:     route,
 		buffer:    buffer,
 		startChan: make(chan struct{}),
-		closeChan: make(chan int),
+		ctx:       ctx,
+		cancel:    cancel,
+		finished:  make(chan struct{}),
+	}
+	if balanced {
+		d.shared = make(chan T, buffer)
+	}
+	if size > 0 {
+		for i := 0; i < size; i++ {
+			d.addSub()
+		}
+		d.sealed = true
 	}
-	d.wg.Add(1)
 	go d.run()
 	return d
 }
 
 func (d *distributeIterator[T]) run() {
-	// TODO
-	// go func(){
-	// 	d.wg.Wait()
-	// }()
-	defer d.iter.Close()
-	<-d.startChan
-	counter := 0
+	defer close(d.finished)
 
-	for {
+	select {
+	case <-d.ctx.Done():
+	case <-d.startChan:
+	}
+
+	var err error
+	for count := 0; d.ctx.Err() == nil && d.source.Next(); count++ {
+		var item T
+		item, err = d.source.Get()
+		if err != nil {
+			break
+		}
+		var sent bool
+		if sent, err = d.send(count, item); !sent || err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = d.source.Err()
+	}
+	d.closeErr = d.source.Close()
+	d.err = err
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.sealed = true
+	if d.shared != nil {
+		close(d.shared)
+		return
+	}
+	for _, sub := range d.subs {
+		close(sub.items)
+	}
+}
+
+// send delivers item to one of the iterators and reports whether
+// distribution should go on.
+func (d *distributeIterator[T]) send(index int, item T) (bool, error) {
+	if d.shared != nil {
 		select {
-		case id := <-d.closeChan:
-			d.lock.Lock()
-			if d.chans[id] != nil {
-				close(d.chans[id])
-				d.chans[id] = nil
-				d.wg.Done()
-			}
-			d.lock.Unlock()
-		default:
-			d.lock.Lock()
-			if !d.iter.Next() {
-				if err := d.iter.Err(); err != nil {
-					d.chans[counter] <- ValErr[T]{*new(T), err}
-				}
-				if err := d.iter.Close(); err != nil {
-					d.chans[counter] <- ValErr[T]{*new(T), err}
-				}
-				for i := range d.chans {
-					if d.chans[i] != nil {
-						close(d.chans[i])
-						d.chans[i] = nil
-					}
-				}
-				// close(d.closeChan)
-			} else {
-				item, err := d.iter.Get()
-				if d.chans[counter] != nil {
-					d.chans[counter] <- ValErr[T]{item, err}
-					counter = (d.count + 1) % len(d.chans)
-					d.count++
+		case <-d.ctx.Done():
+			return false, nil
+		case d.shared <- item:
+			return true, nil
+		}
+	}
+
+	target := -1
+	if d.route != nil {
+		var err error
+		if target, err = d.route(index, item); err != nil {
+			return false, err
+		}
+	}
+
+	for {
+		d.lock.Lock()
+		var sub *distributedIterator[T]
+		if target >= 0 {
+			sub = d.subs[target]
+		} else {
+			for tries := 0; tries < len(d.subs) && sub == nil; tries++ {
+				if !d.subs[d.turn].isClosed() {
+					sub = d.subs[d.turn]
 				}
+				d.turn = (d.turn + 1) % len(d.subs)
 			}
-			d.lock.Unlock()
 		}
+		d.lock.Unlock()
+
+		if sub == nil {
+			return false, nil
+		}
+
+		select {
+		case <-d.ctx.Done():
+			return false, nil
+		case <-sub.closed:
+			if target >= 0 {
+				// the consumer for this key is gone so the item is dropped
+				return true, nil
+			}
+		case sub.items <- item:
+			return true, nil
+		}
+	}
+}
+
+// addSub creates a new iterator. It must be called with the lock held.
+func (d *distributeIterator[T]) addSub() {
+	sub := &distributedIterator[T]{
+		parent: d,
+		items:  d.shared,
+		closed: make(chan struct{}),
+	}
+	if sub.items == nil {
+		sub.items = make(chan T, d.buffer)
+	}
+	d.subs = append(d.subs, sub)
+	d.openCount++
+}
+
+// startDistributing must be called with the lock held.
+func (d *distributeIterator[T]) startDistributing() {
+	if !d.started {
+		d.started = true
+		close(d.startChan)
 	}
 }
 
+// stopIfUnused stops the distribution once no iterator is left to consume items.
+// It must be called with the lock held.
+func (d *distributeIterator[T]) stopIfUnused() bool {
+	if d.openCount > 0 || !(d.sealed || d.started) {
+		return false
+	}
+	d.cancel()
+	return true
+}
+
 func (d *distributeIterator[T]) Next() bool {
 	d.lock.Lock()
 	defer d.lock.Unlock()
-	if d.done {
+
+	d.curr = nil
+	if d.ctx.Err() != nil {
 		return false
 	}
-
-	if d.startChan != nil {
-		d.startChan <- struct{}{}
-		close(d.startChan)
-		d.startChan = nil
+	if d.created == len(d.subs) {
+		if d.sealed {
+			return false
+		}
+		d.addSub()
 	}
-
-	id := len(d.chans)
-	c := make(chan ValErr[T], d.buffer)
-	d.chans = append(d.chans, c)
-	d.curr = OnClose(FromValErrChannel(c), func() error {
-		d.closeChan <- id
-		return nil
-	})
-	d.wg.Add(1)
+	d.curr = d.subs[d.created]
+	d.created++
 
 	return true
 }
 
 func (d *distributeIterator[T]) Get() (Iterator[T], error) {
-	d.lock.RLock()
-	defer d.lock.RUnlock()
-	return d.curr, d.err
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.curr, nil
 }
 
+// Close stops creating iterators and starts the distribution.
+// Iterators not yet returned by Next are closed.
 func (d *distributeIterator[T]) Close() error {
 	d.lock.Lock()
-	defer d.lock.Unlock()
-	if d.done {
-		return d.err
+	d.sealed = true
+	for _, sub := range d.subs[d.created:] {
+		sub.once.Do(func() {
+			close(sub.closed)
+			d.openCount--
+		})
+	}
+	d.created = len(d.subs)
+	d.startDistributing()
+	stopped := d.stopIfUnused()
+	d.lock.Unlock()
+
+	if stopped {
+		<-d.finished
+		return d.closeErr
 	}
-	d.done = true
-	d.wg.Done()
 	return nil
 }
 
-func (d *distributeIterator[T]) Err() error {
-	d.lock.RLock()
-	defer d.lock.RUnlock()
-	return d.err
+func (d *distributeIterator[T]) Err() error { return nil }
+
+func (sub *distributedIterator[T]) isClosed() bool {
+	select {
+	case <-sub.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (sub *distributedIterator[T]) Next() bool {
+	if sub.finished || sub.isClosed() {
+		return false
+	}
+
+	d := sub.parent
+	d.lock.Lock()
+	d.startDistributing()
+	d.lock.Unlock()
+
+	item, ok := <-sub.items
+	if !ok {
+		<-d.finished
+		sub.err = d.err
+		sub.curr = *new(T)
+		sub.finished = true
+		return false
+	}
+
+	sub.curr = item
+	return true
+}
+
+func (sub *distributedIterator[T]) Get() (T, error) { return sub.curr, sub.err }
+func (sub *distributedIterator[T]) Err() error      { return sub.err }
+
+func (sub *distributedIterator[T]) Close() error {
+	var stopped bool
+	sub.once.Do(func() {
+		d := sub.parent
+		d.lock.Lock()
+		close(sub.closed)
+		d.openCount--
+		stopped = d.stopIfUnused()
+		d.lock.Unlock()
+	})
+	if stopped {
+		<-sub.parent.finished
+		return sub.parent.closeErr
+	}
+	return nil
 }
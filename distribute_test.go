@@ -1,9 +1,12 @@
 package iterator
 
 import (
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -29,3 +32,115 @@ func TestDistribute(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func sequence(n int) []int {
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i
+	}
+	return items
+}
+
+func consumeAll[T any](t *testing.T, iters []Iterator[T]) [][]T {
+	results := make([][]T, len(iters))
+	errs := make([]error, len(iters))
+	var wg sync.WaitGroup
+	wg.Add(len(iters))
+	for i := range iters {
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = ToSlice(iters[i])
+		}(i)
+	}
+	wg.Wait()
+	for i := range errs {
+		require.NoError(t, errs[i])
+	}
+	return results
+}
+
+func TestDistributeTermination(t *testing.T) {
+	var closed atomic.Int32
+	d := Distribute[int](0)(closeTracker(Range(0, 99, 1), &closed))
+	dists, err := ToSlice(Limit[Iterator[int]](4)(d))
+	require.NoError(t, err)
+
+	var all []int
+	for _, items := range consumeAll(t, dists) {
+		all = append(all, items...)
+	}
+	assert.ElementsMatch(t, sequence(100), all)
+	assert.EqualValues(t, 1, closed.Load())
+	<-d.(*distributeIterator[int]).finished
+}
+
+func TestDistributeEarlyClose(t *testing.T) {
+	var closed atomic.Int32
+	dists, err := ToSlice(Limit[Iterator[int]](2)(Distribute[int](0)(closeTracker(Ascending(0, 1), &closed))))
+	require.NoError(t, err)
+
+	require.NoError(t, dists[1].Close())
+	assert.EqualValues(t, 0, closed.Load())
+	checkIteratorEqual(t, Limit[int](3)(dists[0]), []int{0, 1, 2})
+	assert.EqualValues(t, 1, closed.Load())
+}
+
+func TestDistributeError(t *testing.T) {
+	errTest := errors.New("test error")
+	iter := Map(func(_ int, item int) (int, error) {
+		if item == 4 {
+			return 0, errTest
+		}
+		return item, nil
+	})(Range(0, 9, 1))
+
+	dists, err := ToSlice(Limit[Iterator[int]](2)(Distribute[int](1)(iter)))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(len(dists))
+	for i := range dists {
+		go func(i int) {
+			defer wg.Done()
+			_, err := ToSlice(dists[i])
+			assert.ErrorIs(t, err, errTest)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestDistributeBy(t *testing.T) {
+	iter := FromSlice([]string{"a1", "b1", "a2", "c1", "b2", "a3", "c2"})
+	dists, err := ToSlice(DistributeBy(3, func(_ int, item string) (byte, error) {
+		return item[0], nil
+	})(iter))
+	require.NoError(t, err)
+	require.Len(t, dists, 3)
+
+	var all []string
+	for _, items := range consumeAll(t, dists) {
+		keys := make(map[byte]struct{})
+		for _, item := range items {
+			keys[item[0]] = struct{}{}
+		}
+		for _, other := range all {
+			_, ok := keys[other[0]]
+			assert.False(t, ok, "key %c consumed by multiple iterators", other[0])
+		}
+		all = append(all, items...)
+	}
+	assert.ElementsMatch(t, []string{"a1", "b1", "a2", "c1", "b2", "a3", "c2"}, all)
+
+	assert.Panics(t, func() { DistributeBy(0, func(_ int, item string) (byte, error) { return item[0], nil }) })
+}
+
+func TestDistributeBalanced(t *testing.T) {
+	dists, err := ToSlice(Limit[Iterator[int]](3)(DistributeBalanced[int](2)(Range(0, 99, 1))))
+	require.NoError(t, err)
+
+	var all []int
+	for _, items := range consumeAll(t, dists) {
+		all = append(all, items...)
+	}
+	assert.ElementsMatch(t, sequence(100), all)
+}
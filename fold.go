@@ -1,6 +1,10 @@
 package iterator
 
-import "golang.org/x/exp/constraints"
+import (
+	"context"
+
+	"golang.org/x/exp/constraints"
+)
 
 // Fold all items into a single value with a start value by applying fn on all items
 func Fold[T any, S any](iter Iterator[T], start S, fn func(int, T, S) (S, error)) (S, error) {
@@ -21,6 +25,11 @@ func Fold[T any, S any](iter Iterator[T], start S, fn func(int, T, S) (S, error)
 	return reduced, nil
 }
 
+// FoldContext is like Fold but stops with ctx.Err() once ctx is done
+func FoldContext[T any, S any](ctx context.Context, iter Iterator[T], start S, fn func(int, T, S) (S, error)) (S, error) {
+	return Fold(WithContext[T](ctx)(iter), start, fn)
+}
+
 // Reduce all items into a single value by applying fn on all items
 func Reduce[T any](iter Iterator[T], fn func(int, T, T) (T, error)) (T, error) {
 	if !iter.Next() {
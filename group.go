@@ -5,9 +5,11 @@ import (
 )
 
 // GroupFunc
-func GroupFunc[T any, S comparable](fn func(int, T) (S, error)) Modifier[T, Iterator[T]] {
+// Items of each group are buffered until the group is consumed,
+// opts control how they are buffered.
+func GroupFunc[T any, S comparable](fn func(int, T) (S, error), opts ...BufferOption[T]) Modifier[T, Iterator[T]] {
 	return func(iter Iterator[T]) Iterator[Iterator[T]] {
-		g := newGroupsHandler(iter, fn)
+		g := newGroupsHandler(iter, fn, newBufferConfig(opts))
 		go g.handle()
 		return g.iterator
 	}
@@ -20,7 +22,7 @@ func Group[T comparable](iter Iterator[T]) Iterator[Iterator[T]] {
 	})(iter)
 }
 
-func uniquesFunc[T any, S comparable](fn func(int, T) (S, error), uniquesOnly bool) Modifier[T, T] {
+func uniquesFunc[T any, S comparable](fn func(int, T) (S, error), uniquesOnly bool, opts []BufferOption[T]) Modifier[T, T] {
 	return func(iter Iterator[T]) Iterator[T] {
 		return FilterMap(func(_ int, group Iterator[T]) (T, bool, error) {
 			var item T
@@ -39,37 +41,38 @@ func uniquesFunc[T any, S comparable](fn func(int, T) (S, error), uniquesOnly bo
 				return *new(T), false, nil
 			}
 			return item, true, nil
-		})(GroupFunc(fn)(iter))
+		})(GroupFunc(fn, opts...)(iter))
 	}
 }
 
 // DuplicatesFunc
-func DuplicatesFunc[T any, S comparable](fn func(int, T) (S, error)) Modifier[T, T] {
-	return uniquesFunc(fn, false)
+func DuplicatesFunc[T any, S comparable](fn func(int, T) (S, error), opts ...BufferOption[T]) Modifier[T, T] {
+	return uniquesFunc(fn, false, opts)
 }
 
 // Duplicates
-func Duplicates[T comparable](iter Iterator[T]) Iterator[T] {
+func Duplicates[T comparable](iter Iterator[T], opts ...BufferOption[T]) Iterator[T] {
 	return DuplicatesFunc(func(_ int, item T) (T, error) {
 		return item, nil
-	})(iter)
+	}, opts...)(iter)
 }
 
 // UniquesFunc
-func UniquesFunc[T any, S comparable](fn func(int, T) (S, error)) Modifier[T, T] {
-	return uniquesFunc(fn, true)
+func UniquesFunc[T any, S comparable](fn func(int, T) (S, error), opts ...BufferOption[T]) Modifier[T, T] {
+	return uniquesFunc(fn, true, opts)
 }
 
 // Uniques
-func Uniques[T comparable](iter Iterator[T]) Iterator[T] {
+func Uniques[T comparable](iter Iterator[T], opts ...BufferOption[T]) Iterator[T] {
 	return UniquesFunc(func(_ int, item T) (T, error) {
 		return item, nil
-	})(iter)
+	}, opts...)(iter)
 }
 
 type groupsHandler[T any, S comparable] struct {
 	source Iterator[T]
 	fn     func(int, T) (S, error)
+	config *bufferConfig[T]
 
 	parentNextChan    chan *buffer.Iterator[Iterator[T]]
 	parentCloseChan   chan *buffer.Iterator[Iterator[T]]
@@ -78,26 +81,31 @@ type groupsHandler[T any, S comparable] struct {
 
 	parentBuffer *buffer.Buffer[Iterator[T]]
 	iterator     *buffer.Iterator[Iterator[T]]
+	done         *buffer.Done
 
 	keys            map[S]int
 	childrenBuffers []*buffer.Buffer[T]
 
-	finished    bool
-	err         error
-	closedCount int
-	count       int
+	finished     bool
+	err          error
+	sourceClosed bool
+	parentClosed bool
+	openChildren int
+	count        int
 }
 
-func newGroupsHandler[T any, S comparable](source Iterator[T], fn func(int, T) (S, error)) *groupsHandler[T, S] {
+func newGroupsHandler[T any, S comparable](source Iterator[T], fn func(int, T) (S, error), config *bufferConfig[T]) *groupsHandler[T, S] {
 	parentNextChan := make(chan *buffer.Iterator[Iterator[T]])
 	parentCloseChan := make(chan *buffer.Iterator[Iterator[T]])
 	childrenNextChan := make(chan *buffer.Iterator[T])
 	childrenCloseChan := make(chan *buffer.Iterator[T])
 	parentBuffer := buffer.New[Iterator[T]]()
+	done := buffer.NewDone()
 
 	return &groupsHandler[T, S]{
 		source: source,
 		fn:     fn,
+		config: config,
 
 		parentNextChan:    parentNextChan,
 		parentCloseChan:   parentCloseChan,
@@ -105,35 +113,102 @@ func newGroupsHandler[T any, S comparable](source Iterator[T], fn func(int, T) (
 		childrenCloseChan: childrenCloseChan,
 
 		parentBuffer: parentBuffer,
-		iterator:     buffer.NewIterator(parentBuffer, parentNextChan, parentCloseChan),
+		iterator:     buffer.NewIterator(parentBuffer, parentNextChan, parentCloseChan, done),
+		done:         done,
 
 		keys: make(map[S]int),
 	}
 }
 
+// handle serves the parent and children iterators until the source fails,
+// or it is exhausted and the parent is closed or exhausted, or the parent
+// and all groups handed out are closed.
 func (g *groupsHandler[T, S]) handle() {
 	for {
 		select {
 		case parent := <-g.parentNextChan:
 			g.nextParent(parent)
 		case parent := <-g.parentCloseChan:
-			parent.SendErr(nil)
+			g.closeParent(parent)
 		case child := <-g.childrenNextChan:
 			g.nextChildren(child)
 		case child := <-g.childrenCloseChan:
+			g.openChildren--
+			if g.parentClosed && g.openChildren == 0 {
+				err := g.closeSource()
+				child.SendErr(err)
+				g.done.Close(err)
+				return
+			}
 			child.SendErr(nil)
 		}
+
+		if g.err != nil {
+			g.dropQueued()
+			g.closeSource()
+			g.done.Close(g.err)
+			return
+		}
+		if g.finished && g.parentClosed {
+			g.done.Close(nil)
+			return
+		}
+	}
+}
+
+// dropQueued closes the groups never handed out, nobody else can close them
+func (g *groupsHandler[T, S]) dropQueued() {
+	for {
+		item, ok, _ := g.parentBuffer.Pop()
+		if !ok {
+			return
+		}
+		item.(*buffer.Iterator[T]).Buffer.Close()
+		g.openChildren--
+	}
+}
+
+func (g *groupsHandler[T, S]) closeSource() error {
+	if g.sourceClosed {
+		return nil
+	}
+	g.sourceClosed = true
+	return g.source.Close()
+}
+
+func (g *groupsHandler[T, S]) closeParent(parent *buffer.Iterator[Iterator[T]]) {
+	g.parentClosed = true
+	g.dropQueued()
+
+	if g.openChildren == 0 {
+		err := g.closeSource()
+		parent.SendErr(err)
+		g.finished = true
+		return
 	}
+	parent.SendErr(nil)
+}
+
+func (g *groupsHandler[T, S]) newChild(item T) (*buffer.Iterator[T], error) {
+	buf := g.config.newBuffer()
+	g.childrenBuffers = append(g.childrenBuffers, buf)
+	if err := buf.Push(item); err != nil {
+		buf.Close()
+		return nil, err
+	}
+	g.openChildren++
+	return buffer.NewIterator(buf, g.childrenNextChan, g.childrenCloseChan, g.done), nil
 }
 
 func (g *groupsHandler[T, S]) nextParent(parent *buffer.Iterator[Iterator[T]]) {
-	item, ok := parent.Buffer.Pop()
+	item, ok, _ := parent.Buffer.Pop()
 	if ok {
 		parent.SendItem(item)
 		return
 	}
 
 	if g.finished {
+		g.parentClosed = true
 		parent.End()
 		return
 	}
@@ -152,6 +227,7 @@ func (g *groupsHandler[T, S]) nextParent(parent *buffer.Iterator[Iterator[T]]) {
 
 	if !hasMore {
 		g.finished = true
+		g.parentClosed = true
 		parent.End()
 		return
 	}
@@ -160,13 +236,19 @@ func (g *groupsHandler[T, S]) nextParent(parent *buffer.Iterator[Iterator[T]]) {
 }
 
 func (g *groupsHandler[T, S]) nextChildren(child *buffer.Iterator[T]) {
-	item, ok := child.Buffer.Pop()
+	item, ok, err := child.Buffer.Pop()
+	if err != nil {
+		g.err = err
+		child.SendErr(err)
+		return
+	}
 	if ok {
 		child.SendItem(item)
 		return
 	}
 
 	if g.finished {
+		g.openChildren--
 		child.End()
 		return
 	}
@@ -185,6 +267,7 @@ func (g *groupsHandler[T, S]) nextChildren(child *buffer.Iterator[T]) {
 
 	if !hasMore {
 		g.finished = true
+		g.openChildren--
 		child.End()
 		return
 	}
@@ -196,7 +279,7 @@ func (g *groupsHandler[T, S]) progress() (T, S, bool, error) {
 	hasMore := g.source.Next()
 	if !hasMore {
 		// auto close source
-		closeErr := g.source.Close()
+		closeErr := g.closeSource()
 		if err := g.source.Err(); err != nil {
 			return *new(T), *new(S), false, err
 		}
@@ -227,18 +310,18 @@ func (g *groupsHandler[T, S]) progressParent() (Iterator[T], bool, error) {
 
 		keyBufferIndex, ok := g.keys[key]
 		if ok {
-			keyBuffer := g.childrenBuffers[keyBufferIndex]
-			if !keyBuffer.IsClosed() {
-				keyBuffer.Push(item)
+			if err := g.childrenBuffers[keyBufferIndex].Push(item); err != nil {
+				return nil, false, err
 			}
 			continue
 		}
 
-		buf := buffer.New[T]()
-		buf.Push(item)
 		g.keys[key] = len(g.childrenBuffers)
-		g.childrenBuffers = append(g.childrenBuffers, buf)
-		return buffer.NewIterator(buf, g.childrenNextChan, g.childrenCloseChan), true, nil
+		child, err := g.newChild(item)
+		if err != nil {
+			return nil, false, err
+		}
+		return child, true, nil
 	}
 }
 
@@ -251,11 +334,18 @@ func (g *groupsHandler[T, S]) progressChild(child *buffer.Iterator[T]) (T, bool,
 
 		keyBufferIndex, ok := g.keys[key]
 		if !ok {
-			buf := buffer.New[T]()
-			buf.Push(item)
 			g.keys[key] = len(g.childrenBuffers)
-			g.childrenBuffers = append(g.childrenBuffers, buf)
-			g.parentBuffer.Push(buffer.NewIterator(buf, g.childrenNextChan, g.childrenCloseChan))
+			if g.parentClosed {
+				buf := buffer.New[T]()
+				buf.Close()
+				g.childrenBuffers = append(g.childrenBuffers, buf)
+				continue
+			}
+			newChild, err := g.newChild(item)
+			if err != nil {
+				return *new(T), false, err
+			}
+			g.parentBuffer.Push(newChild)
 			continue
 		}
 
@@ -263,8 +353,8 @@ func (g *groupsHandler[T, S]) progressChild(child *buffer.Iterator[T]) (T, bool,
 		if keyBuffer == child.Buffer {
 			return item, true, nil
 		}
-		if !keyBuffer.IsClosed() {
-			keyBuffer.Push(item)
+		if err := keyBuffer.Push(item); err != nil {
+			return *new(T), false, err
 		}
 	}
 }
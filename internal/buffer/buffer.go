@@ -4,40 +4,74 @@ import (
 	"sync"
 )
 
+// Queue holds the items of a Buffer in FIFO order
+type Queue[T any] interface {
+	Push(T) error
+	Pop() (T, bool, error)
+	Len() int
+	Close() error
+}
+
+type memoryQueue[T any] struct {
+	items []T
+}
+
+func (q *memoryQueue[T]) Push(val T) error {
+	q.items = append(q.items, val)
+	return nil
+}
+
+func (q *memoryQueue[T]) Pop() (T, bool, error) {
+	if len(q.items) == 0 {
+		return *new(T), false, nil
+	}
+	head := q.items[0]
+	q.items[0] = *new(T)
+	q.items = q.items[1:]
+	return head, true, nil
+}
+
+func (q *memoryQueue[T]) Len() int     { return len(q.items) }
+func (q *memoryQueue[T]) Close() error { q.items = nil; return nil }
+
 type Buffer[T any] struct {
-	buffer []T
+	queue  Queue[T]
 	lock   sync.Mutex
 	closed bool
 }
 
-func (b *Buffer[T]) Push(val T) {
+func (b *Buffer[T]) Push(val T) error {
 	b.lock.Lock()
 	defer b.lock.Unlock()
-	b.buffer = append(b.buffer, val)
+	if b.closed {
+		return nil
+	}
+	return b.queue.Push(val)
 }
 
-func (b *Buffer[T]) Pop() (T, bool) {
+func (b *Buffer[T]) Pop() (T, bool, error) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
-	if len(b.buffer) == 0 {
-		return *new(T), false
+	if b.closed {
+		return *new(T), false, nil
 	}
-	head := b.buffer[0]
-	b.buffer = b.buffer[1:]
-	return head, true
+	return b.queue.Pop()
 }
 
-func (b *Buffer[T]) Close() {
+func (b *Buffer[T]) Close() error {
 	b.lock.Lock()
 	defer b.lock.Unlock()
-	b.buffer = nil
+	if b.closed {
+		return nil
+	}
 	b.closed = true
+	return b.queue.Close()
 }
 
 func (b *Buffer[T]) IsEmpty() bool {
 	b.lock.Lock()
 	defer b.lock.Unlock()
-	return len(b.buffer) == 0
+	return b.closed || b.queue.Len() == 0
 }
 
 func (b *Buffer[T]) IsClosed() bool {
@@ -47,14 +81,43 @@ func (b *Buffer[T]) IsClosed() bool {
 }
 
 func New[T any]() *Buffer[T] {
-	return &Buffer[T]{}
+	return NewWithQueue[T](&memoryQueue[T]{})
+}
+
+func NewWithQueue[T any](queue Queue[T]) *Buffer[T] {
+	return &Buffer[T]{queue: queue}
+}
+
+// Done is closed by the handler serving buffered iterators once it stops.
+// Iterators drain their own buffers afterwards and then report Err.
+type Done struct {
+	c    chan struct{}
+	once sync.Once
+	err  error
+}
+
+func NewDone() *Done {
+	return &Done{c: make(chan struct{})}
 }
 
+func (d *Done) Close(err error) {
+	d.once.Do(func() {
+		d.err = err
+		close(d.c)
+	})
+}
+
+func (d *Done) C() <-chan struct{} { return d.c }
+
+// Err must only be called after C is closed
+func (d *Done) Err() error { return d.err }
+
 type Iterator[T any] struct {
 	Buffer *Buffer[T]
 
 	nextChan  chan<- *Iterator[T]
 	closeChan chan<- *Iterator[T]
+	done      *Done
 
 	itemChan chan T
 	errChan  chan error
@@ -75,18 +138,45 @@ func (iter *Iterator[T]) Next() bool {
 	select {
 	case iter.nextChan <- iter:
 		return iter.waitNext()
+	case <-iter.done.C():
+		return iter.drain()
 	default:
-		item, ok := iter.Buffer.Pop()
+		item, ok, err := iter.Buffer.Pop()
+		if err != nil {
+			// the handler must know this iterator is gone
+			iter.Close()
+			iter.err = err
+			return false
+		}
 		if ok {
 			iter.curr = item
 			return true
 		}
 
-		iter.nextChan <- iter
-		return iter.waitNext()
+		select {
+		case iter.nextChan <- iter:
+			return iter.waitNext()
+		case <-iter.done.C():
+			return iter.drain()
+		}
 	}
 }
 
+func (iter *Iterator[T]) drain() bool {
+	item, ok, err := iter.Buffer.Pop()
+	if ok && err == nil {
+		iter.curr = item
+		return true
+	}
+
+	iter.err = err
+	if iter.err == nil {
+		iter.err = iter.done.Err()
+	}
+	iter.close()
+	return false
+}
+
 func (iter *Iterator[T]) waitNext() bool {
 	select {
 	case val, ok := <-iter.itemChan:
@@ -109,9 +199,11 @@ func (iter *Iterator[T]) Close() error {
 	if iter.Buffer.IsClosed() || iter.err != nil {
 		return iter.err
 	}
-	iter.closeChan <- iter
-	err := <-iter.errChan
-	iter.err = err
+	select {
+	case iter.closeChan <- iter:
+		iter.err = <-iter.errChan
+	case <-iter.done.C():
+	}
 	iter.close()
 	return iter.err
 }
@@ -131,12 +223,13 @@ func (iter *Iterator[T]) close() {
 func (iter *Iterator[T]) Get() (T, error) { return iter.curr, iter.err }
 func (iter *Iterator[T]) Err() error      { return iter.err }
 
-func NewIterator[T any](buffer *Buffer[T], nextChan chan<- *Iterator[T], closeChan chan<- *Iterator[T]) *Iterator[T] {
+func NewIterator[T any](buffer *Buffer[T], nextChan chan<- *Iterator[T], closeChan chan<- *Iterator[T], done *Done) *Iterator[T] {
 	return &Iterator[T]{
 		Buffer: buffer,
 
 		nextChan:  nextChan,
 		closeChan: closeChan,
+		done:      done,
 
 		itemChan: make(chan T),
 		errChan:  make(chan error),
@@ -7,6 +7,8 @@ import (
 var (
 	ErrNoItems    = errors.New("iterator: no items in iterator")
 	ErrMultiItems = errors.New("iterator: multiple items in iterator")
+	ErrNotSorted  = errors.New("iterator: items are not sorted")
+	ErrUnequalLen = errors.New("iterator: iterators have different lengths")
 )
 
 // Iterator defines the methods needed to conform to an iterator supported by this package
@@ -52,8 +52,13 @@ func FromSlice[T any](source []T) Iterator[T] {
 
 // ToSlice consumes all items in the iterator into a slice
 func ToSlice[T any](iter Iterator[T]) ([]T, error) {
+	return ToSliceContext(context.Background(), iter)
+}
+
+// ToSliceContext is like ToSlice but stops with ctx.Err() once ctx is done
+func ToSliceContext[T any](ctx context.Context, iter Iterator[T]) ([]T, error) {
 	data := []T{}
-	_, err := Iterate(iter, func(_ int, item T) (bool, error) {
+	_, err := IterateContext(ctx, iter, func(_ int, item T) (bool, error) {
 		data = append(data, item)
 		return true, nil
 	})
@@ -141,28 +146,43 @@ func (iter *valErrChannelIterator[T]) Get() (T, error) { return iter.value, iter
 func (iter *valErrChannelIterator[T]) Close() error    { return nil }
 func (iter *valErrChannelIterator[T]) Err() error      { return iter.err }
 
-// ToChannel consumes all items in the iterator into a channel with size as capacity
+// ToChannel consumes all items in the iterator into a channel with size as capacity.
+// The returned function stops consuming the iterator and closes it.
 func ToChannel[T any](iter Iterator[T], size int) (<-chan ValErr[T], func()) {
+	return ToChannelContext(context.Background(), iter, size)
+}
+
+// ToChannelContext is like ToChannel but also stops consuming the iterator once ctx is done.
+// The error from ctx is sent as the last item in the channel.
+func ToChannelContext[T any](ctx context.Context, iter Iterator[T], size int) (<-chan ValErr[T], func()) {
 	stream := make(chan ValErr[T], size)
-	cancel := make(chan struct{})
+	stopCtx, stop := context.WithCancel(context.Background())
 
 	go func() {
-		_, err := Iterate(iter, func(_ int, item T) (bool, error) {
+		defer close(stream)
+
+		err := pull(WithContext[T](ctx)(iter), func(_ int, item T) bool {
 			select {
-			case <-cancel:
-				close(stream)
-				return false, nil
+			case <-stopCtx.Done():
+				return false
+			case <-ctx.Done():
+				return false
 			case stream <- ValErr[T]{Val: item}:
-				return true, nil
+				return true
 			}
 		})
+		if err == nil {
+			err = ctx.Err()
+		}
 		if err != nil {
-			stream <- ValErr[T]{Err: err}
+			select {
+			case <-stopCtx.Done():
+			case stream <- ValErr[T]{Err: err}:
+			}
 		}
-		close(stream)
 	}()
 
-	return stream, func() { cancel <- struct{}{} }
+	return stream, stop
 }
 
 // FromMap returns an iterator wrapping a map source
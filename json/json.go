@@ -1,11 +1,15 @@
 package json
 
 import (
+	"bufio"
 	"bytes"
+	"encoding"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"reflect"
+	"strconv"
 
 	it "github.com/wlMalk/iterator"
 )
@@ -117,10 +121,16 @@ func (r *Reader[T]) Err() error { return r.err }
 type Writer[T any] struct {
 	writer io.Writer
 	iter   it.Iterator[T]
-	array  bool
+	// open and close surround the items in array and object mode, items are
+	// otherwise written one per line
+	open  byte
+	close byte
+	entry func(w *Writer[T], item T) ([]byte, error)
 
 	indentPrefix string
 	indentValue  string
+	escapeHTML   bool
+	flushEvery   int
 }
 
 func (w *Writer[T]) Indent(prefix, indent string) {
@@ -128,59 +138,129 @@ func (w *Writer[T]) Indent(prefix, indent string) {
 	w.indentValue = indent
 }
 
+// EscapeHTML sets whether <, > and & are escaped in strings, they are by default
+func (w *Writer[T]) EscapeHTML(escape bool) { w.escapeHTML = escape }
+
+// FlushEvery buffers the output and flushes it every n items, along with the
+// underlying writer if it has a Flush method like http.ResponseWriter
+func (w *Writer[T]) FlushEvery(n int) { w.flushEvery = n }
+
+func (w *Writer[T]) encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(w.escapeHTML)
+	enc.SetIndent(w.indentPrefix, w.indentValue)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
 func (w *Writer[T]) Write() error {
-	if w.array {
-		if _, err := w.writer.Write([]byte{'['}); err != nil {
+	out := w.writer
+	var buffered *bufio.Writer
+	if w.flushEvery > 0 {
+		buffered = bufio.NewWriter(w.writer)
+		out = buffered
+	}
+	flush := func() error {
+		if buffered == nil {
+			return nil
+		}
+		if err := buffered.Flush(); err != nil {
 			return err
 		}
+		if f, ok := w.writer.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+		return nil
+	}
 
-		_, err := it.Iterate(w.iter, func(index int, item T) (bool, error) {
-			if index > 0 {
-				if _, err := w.writer.Write([]byte{','}); err != nil {
-					return false, err
-				}
-			}
-
-			var b []byte
-			var err error
-			if len(w.indentPrefix) > 0 || len(w.indentValue) > 0 {
-				b, err = json.Marshal(item)
-				if err != nil {
-					return false, err
-				}
-			} else {
-				b, err = json.MarshalIndent(item, w.indentPrefix, w.indentValue)
-				if err != nil {
-					return false, err
-				}
-			}
+	if w.open != 0 {
+		if _, err := out.Write([]byte{w.open}); err != nil {
+			w.iter.Close()
+			return err
+		}
+	}
 
-			_, err = w.writer.Write(b)
-			if err != nil {
+	_, err := it.Iterate(w.iter, func(index int, item T) (bool, error) {
+		if index > 0 && w.open != 0 {
+			if _, err := out.Write([]byte{','}); err != nil {
 				return false, err
 			}
-
-			return true, nil
-		})
-
-		if _, err := w.writer.Write([]byte{']'}); err != nil {
-			return err
 		}
 
-		return err
-	} else {
-		enc := json.NewEncoder(w.writer)
-		if len(w.indentPrefix) > 0 || len(w.indentValue) > 0 {
-			enc.SetIndent(w.indentPrefix, w.indentValue)
+		b, err := w.entry(w, item)
+		if err != nil {
+			return false, err
+		}
+		if w.open == 0 {
+			b = append(b, '\n')
+		}
+		if _, err := out.Write(b); err != nil {
+			return false, err
 		}
-		_, err := it.Iterate(w.iter, func(_ int, item T) (bool, error) {
-			if err := enc.Encode(item); err != nil {
+
+		if buffered != nil && (index+1)%w.flushEvery == 0 {
+			if err := flush(); err != nil {
 				return false, err
 			}
-			return true, nil
-		})
+		}
+		return true, nil
+	})
+	if err != nil {
+		w.iter.Close()
 		return err
 	}
+
+	if w.close != 0 {
+		if _, err := out.Write([]byte{w.close}); err != nil {
+			return err
+		}
+	}
+
+	return flush()
+}
+
+func encodeItem[T any](w *Writer[T], item T) ([]byte, error) {
+	return w.encode(item)
+}
+
+func encodeEntry[K comparable, V any](w *Writer[it.KV[K, V]], item it.KV[K, V]) ([]byte, error) {
+	key, err := keyString(item.Key)
+	if err != nil {
+		return nil, err
+	}
+	b, err := w.encode(key)
+	if err != nil {
+		return nil, err
+	}
+	value, err := w.encode(item.Val)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(b, ':'), value...), nil
+}
+
+// keyString converts an object key the way encoding/json does for map keys
+func keyString(key any) (string, error) {
+	// string kinds win over TextMarshaler like in encoding/json
+	v := reflect.ValueOf(key)
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	if k, ok := key.(encoding.TextMarshaler); ok {
+		b, err := k.MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("json: unsupported key type %T", key)
 }
 
 func Read[T any](r io.Reader) *Reader[T] {
@@ -200,8 +280,10 @@ func Read[T any](r io.Reader) *Reader[T] {
 
 func Write[T any](w io.Writer, iter it.Iterator[T]) *Writer[T] {
 	return &Writer[T]{
-		writer: w,
-		iter:   iter,
+		writer:     w,
+		iter:       iter,
+		entry:      encodeItem[T],
+		escapeHTML: true,
 	}
 }
 
@@ -213,7 +295,15 @@ func ReadArray[T any](r io.Reader) *Reader[T] {
 
 func WriteArray[T any](w io.Writer, iter it.Iterator[T]) *Writer[T] {
 	writer := Write(w, iter)
-	writer.array = true
+	writer.open, writer.close = '[', ']'
+	return writer
+}
+
+// WriteObject returns a writer streaming the items of iter as the fields of a single object
+func WriteObject[K comparable, V any](w io.Writer, iter it.Iterator[it.KV[K, V]]) *Writer[it.KV[K, V]] {
+	writer := Write(w, iter)
+	writer.open, writer.close = '{', '}'
+	writer.entry = encodeEntry[K, V]
 	return writer
 }
 
@@ -1,7 +1,10 @@
 package json
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	it "github.com/wlMalk/iterator"
@@ -28,3 +31,80 @@ func TestIteratorUnmarshalJSON(t *testing.T) {
 	require.NoError(t, err)
 	checkIteratorEqual[int](t, iter, []int{1, 2, 3, 4, 5})
 }
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, it.Range(1, 3, 1)).Write())
+	assert.Equal(t, "1\n2\n3\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteArray(&buf, it.FromSlice([]string{"<a>", "b"})).Write())
+	assert.Equal(t, `["\u003ca\u003e","b"]`, buf.String())
+
+	buf.Reset()
+	writer := WriteArray(&buf, it.FromSlice([]string{"<a>"}))
+	writer.EscapeHTML(false)
+	require.NoError(t, writer.Write())
+	assert.Equal(t, `["<a>"]`, buf.String())
+}
+
+func TestWriteIndent(t *testing.T) {
+	var buf bytes.Buffer
+	writer := WriteArray(&buf, it.FromSlice([]map[string]int{{"a": 1}}))
+	writer.Indent("", "  ")
+	require.NoError(t, writer.Write())
+	assert.Equal(t, "[{\n  \"a\": 1\n}]", buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteArray(&buf, it.FromSlice([]map[string]int{{"a": 1}})).Write())
+	assert.Equal(t, `[{"a":1}]`, buf.String())
+}
+
+func TestWriteError(t *testing.T) {
+	iterErr := errors.New("iterator error")
+	failing := it.FromFunc(func() (int, bool, error) { return 0, false, iterErr })
+
+	var buf bytes.Buffer
+	err := WriteArray(&buf, failing).Write()
+	assert.ErrorIs(t, err, iterErr)
+	assert.Equal(t, "[", buf.String())
+}
+
+func TestWriteObject(t *testing.T) {
+	var buf bytes.Buffer
+	iter := it.FromSlice([]it.KV[string, []int]{{Key: "a", Val: []int{1}}, {Key: "b", Val: nil}})
+	require.NoError(t, WriteObject(&buf, iter).Write())
+	assert.Equal(t, `{"a":[1],"b":null}`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteObject(&buf, it.FromSlice([]it.KV[int, bool]{{Key: 1, Val: true}})).Write())
+	assert.Equal(t, `{"1":true}`, buf.String())
+
+	var decoded map[string]bool
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, map[string]bool{"1": true}, decoded)
+
+	buf.Reset()
+	require.NoError(t, WriteObject(&buf, it.FromSlice([]it.KV[upperKey, int]{{Key: "a", Val: 1}})).Write())
+	assert.Equal(t, `{"a":1}`, buf.String())
+}
+
+type upperKey string
+
+func (k upperKey) MarshalText() ([]byte, error) { return []byte(strings.ToUpper(string(k))), nil }
+
+type flushRecorder struct {
+	bytes.Buffer
+	flushed []string
+}
+
+func (f *flushRecorder) Flush() { f.flushed = append(f.flushed, f.String()) }
+
+func TestWriteFlushEvery(t *testing.T) {
+	var out flushRecorder
+	writer := WriteArray(&out, it.Range(1, 5, 1))
+	writer.FlushEvery(2)
+	require.NoError(t, writer.Write())
+	assert.Equal(t, "[1,2,3,4,5]", out.String())
+	assert.Equal(t, []string{"[1,2", "[1,2,3,4", "[1,2,3,4,5]"}, out.flushed)
+}
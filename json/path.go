@@ -0,0 +1,213 @@
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	it "github.com/wlMalk/iterator"
+)
+
+var ErrPathNotFound = errors.New("json: path not found")
+
+// PathReader streams the elements of an array, or the values of an object,
+// found at a dotted path of object keys inside a document.
+type PathReader[T any] struct {
+	dec    *json.Decoder
+	closer func() error
+	path   []string
+
+	object   bool
+	started  bool
+	finished bool
+	skipped  map[string]json.RawMessage
+
+	key  string
+	curr T
+	err  error
+}
+
+func (r *PathReader[T]) UseNumber() { r.dec.UseNumber() }
+
+// Key returns the key of the current value when the path is an object, and an empty string for arrays
+func (r *PathReader[T]) Key() string { return r.key }
+
+// Skipped returns the fields passed over on the way to the path, keyed by their dotted path.
+// Fields following the path are added once the reader is exhausted.
+func (r *PathReader[T]) Skipped() map[string]json.RawMessage { return r.skipped }
+
+func (r *PathReader[T]) delim() (json.Delim, error) {
+	tok, err := r.dec.Token()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return 0, ErrInvalidInput
+	}
+	return delim, nil
+}
+
+// skipFields reads the remaining fields of the object at level into skipped,
+// stopping early at the field named until if it is not empty.
+func (r *PathReader[T]) skipFields(level int, until string) (bool, error) {
+	prefix := strings.Join(r.path[:level], ".")
+	if prefix != "" {
+		prefix += "."
+	}
+
+	for r.dec.More() {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return false, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return false, ErrInvalidInput
+		}
+		if until != "" && key == until {
+			return true, nil
+		}
+
+		var value json.RawMessage
+		if err := r.dec.Decode(&value); err != nil {
+			return false, err
+		}
+		r.skipped[prefix+key] = value
+	}
+	return false, nil
+}
+
+// seek walks the document down to the opening of the container at path
+func (r *PathReader[T]) seek() error {
+	for level, key := range r.path {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+			return ErrPathNotFound
+		}
+
+		found, err := r.skipFields(level, key)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrPathNotFound
+		}
+	}
+
+	delim, err := r.delim()
+	if err != nil {
+		return err
+	}
+	switch delim {
+	case '[':
+	case '{':
+		r.object = true
+	default:
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// finish reads the closing of the container at path and the fields following it
+func (r *PathReader[T]) finish() error {
+	if _, err := r.delim(); err != nil {
+		return err
+	}
+	for level := len(r.path) - 1; level >= 0; level-- {
+		if _, err := r.skipFields(level, ""); err != nil {
+			return err
+		}
+		if _, err := r.delim(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PathReader[T]) Next() bool {
+	if r.finished || r.err != nil {
+		return false
+	}
+
+	if !r.started {
+		r.started = true
+		if r.err = r.seek(); r.err != nil {
+			return false
+		}
+	}
+
+	if !r.dec.More() {
+		r.finished = true
+		r.err = r.finish()
+		return false
+	}
+
+	if r.object {
+		tok, err := r.dec.Token()
+		if err != nil {
+			r.err = err
+			return false
+		}
+		key, ok := tok.(string)
+		if !ok {
+			r.err = ErrInvalidInput
+			return false
+		}
+		r.key = key
+	}
+
+	var item T
+	if r.err = r.dec.Decode(&item); r.err != nil {
+		return false
+	}
+	r.curr = item
+
+	return true
+}
+
+func (r *PathReader[T]) Get() (T, error) {
+	return r.curr, r.err
+}
+
+func (r *PathReader[T]) Close() error {
+	if r.closer != nil {
+		return r.closer()
+	}
+	return nil
+}
+
+func (r *PathReader[T]) Err() error { return r.err }
+
+// ReadPath returns a reader streaming the items of the array or object at path,
+// a dotted list of object keys such as "data.items". An empty path reads the top level value.
+// The key of each object value is available from Key, or use ReadPathEntries.
+func ReadPath[T any](r io.Reader, path string) *PathReader[T] {
+	reader := &PathReader[T]{
+		dec:     json.NewDecoder(r),
+		skipped: make(map[string]json.RawMessage),
+	}
+	if path != "" {
+		reader.path = strings.Split(path, ".")
+	}
+
+	if closer, ok := r.(io.ReadCloser); ok {
+		reader.closer = closer.Close
+	}
+	return reader
+}
+
+// ReadPathEntries is like ReadPath but streams each value of the object at path along with its key
+func ReadPathEntries[T any](r io.Reader, path string) it.Iterator[it.KV[string, T]] {
+	reader := ReadPath[T](r, path)
+	return it.Map(func(_ int, item T) (it.KV[string, T], error) {
+		return it.KV[string, T]{Key: reader.Key(), Val: item}, nil
+	})(reader)
+}
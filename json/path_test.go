@@ -0,0 +1,73 @@
+package json
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	it "github.com/wlMalk/iterator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pathDocument = `{
+	"meta": {"version": 2},
+	"data": {
+		"count": 3,
+		"items": [{"id": 1}, {"id": 2}, {"id": 3}],
+		"next": "abc"
+	},
+	"ok": true
+}`
+
+type pathItem struct {
+	ID int `json:"id"`
+}
+
+func TestReadPath(t *testing.T) {
+	reader := ReadPath[pathItem](strings.NewReader(pathDocument), "data.items")
+
+	require.True(t, reader.Next())
+	item, err := reader.Get()
+	require.NoError(t, err)
+	assert.Equal(t, pathItem{ID: 1}, item)
+	assert.Equal(t, map[string]json.RawMessage{
+		"meta":       json.RawMessage(`{"version": 2}`),
+		"data.count": json.RawMessage(`3`),
+	}, reader.Skipped())
+
+	checkIteratorEqual[pathItem](t, reader, []pathItem{{ID: 2}, {ID: 3}})
+	assert.Equal(t, json.RawMessage(`"abc"`), reader.Skipped()["data.next"])
+	assert.Equal(t, json.RawMessage(`true`), reader.Skipped()["ok"])
+}
+
+func TestReadPathObject(t *testing.T) {
+	reader := ReadPath[int](strings.NewReader(`{"scores": {"a": 1, "b": 2}}`), "scores")
+	checkIteratorEqual[int](t, reader, []int{1, 2})
+
+	reader = ReadPath[int](strings.NewReader(`{"scores": {"a": 1, "b": 2}}`), "scores")
+	var keys []string
+	for reader.Next() {
+		keys = append(keys, reader.Key())
+	}
+	require.NoError(t, reader.Err())
+	assert.Equal(t, []string{"a", "b"}, keys)
+
+	entries := ReadPathEntries[int](strings.NewReader(`{"scores": {"a": 1, "b": 2}}`), "scores")
+	checkIteratorEqual[it.KV[string, int]](t, entries, []it.KV[string, int]{{Key: "a", Val: 1}, {Key: "b", Val: 2}})
+
+	reader = ReadPath[int](strings.NewReader(`[1, 2, 3]`), "")
+	checkIteratorEqual[int](t, reader, []int{1, 2, 3})
+}
+
+func TestReadPathNotFound(t *testing.T) {
+	_, err := it.ToSlice[int](ReadPath[int](strings.NewReader(pathDocument), "data.missing"))
+	assert.ErrorIs(t, err, ErrPathNotFound)
+
+	_, err = it.ToSlice[int](ReadPath[int](strings.NewReader(pathDocument), "data.count.items"))
+	assert.ErrorIs(t, err, ErrPathNotFound)
+
+	_, err = it.ToSlice[int](ReadPath[int](strings.NewReader(`{"data": [1`), "data"))
+	assert.Error(t, err)
+}
@@ -1,34 +1,45 @@
 package iterator
 
 import (
+	"container/heap"
 	"context"
 	"sync"
 )
 
-// Merge takes multiple iterators and consumes them concurrently into a single iterator
+// Merge takes multiple iterators and consumes them concurrently into a single iterator.
+// Closing the merged iterator stops all goroutines and closes the given iterators.
+// A given iterator blocked in Next is closed once its Next returns instead, Close does not wait for it.
 func Merge[T any](iters ...Iterator[T]) Iterator[T] {
+	return MergeContext(context.Background(), iters...)
+}
+
+// MergeContext is like Merge but also stops consuming the given iterators once ctx is done.
+func MergeContext[T any](parent context.Context, iters ...Iterator[T]) Iterator[T] {
 	c := make(chan ValErr[T], len(iters))
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(parent)
 
+	srcs := make([]*detachable[T], len(iters))
+	finished := make([]chan struct{}, len(iters))
 	var wg sync.WaitGroup
 	wg.Add(len(iters))
 
 	for i := range iters {
+		srcs[i] = &detachable[T]{iter: iters[i]}
+		finished[i] = make(chan struct{})
 		go func(i int) {
 			defer func() {
+				close(finished[i])
 				wg.Done()
 			}()
-			var stopped bool
-			_, err := Iterate(iters[i], func(index int, item T) (bool, error) {
+			err := drain(srcs[i], func(_ int, item T) error {
 				select {
 				case <-ctx.Done():
-					stopped = true
-					return false, nil
+					return ctx.Err()
 				case c <- ValErr[T]{Val: item, Err: nil}:
 				}
-				return true, nil
+				return nil
 			})
-			if stopped {
+			if ctx.Err() != nil {
 				return
 			}
 			if err != nil {
@@ -37,15 +48,113 @@ func Merge[T any](iters ...Iterator[T]) Iterator[T] {
 				case c <- ValErr[T]{Val: *new(T), Err: err}:
 					cancel()
 				}
+				return
 			}
+			srcs[i].Close()
 		}(i)
 	}
 
 	go func() {
 		wg.Wait()
-		cancel()
 		close(c)
 	}()
 
-	return FromValErrChannel(c)
+	return WithContext[T](parent)(OnClose(FromValErrChannel(c), func() error {
+		cancel()
+		var err error
+		for i, src := range srcs {
+			if !src.detach() {
+				continue
+			}
+			<-finished[i]
+			if closeErr := src.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}))
+}
+
+// MergeSorted merges iterators already sorted by less into a single sorted iterator.
+// Items are pulled lazily and equal items are taken from earlier iterators first.
+func MergeSorted[T any](less func(a, b T) bool, iters ...Iterator[T]) Iterator[T] {
+	h := &sortHeap[T]{less: less}
+	var started bool
+	var last = -1
+	var curr T
+	var err error
+
+	// advance pulls the next item of the iterator at index into the heap
+	advance := func(index int, replace bool) bool {
+		var item T
+		if iters[index].Next() {
+			item, err = iters[index].Get()
+		} else {
+			err = iters[index].Err()
+			if err == nil && replace {
+				heap.Pop(h)
+			}
+			return err == nil
+		}
+		if err != nil {
+			return false
+		}
+
+		if replace {
+			h.items[0].item = item
+			heap.Fix(h, 0)
+		} else {
+			h.items = append(h.items, sortHeapItem[T]{item: item, index: index})
+		}
+		return true
+	}
+
+	return &iterator[T]{
+		next: func() bool {
+			if err != nil {
+				return false
+			}
+
+			if !started {
+				started = true
+				for i := range iters {
+					if !advance(i, false) {
+						return false
+					}
+				}
+				heap.Init(h)
+			} else if last >= 0 {
+				if !advance(last, true) {
+					return false
+				}
+			}
+
+			if h.Len() == 0 {
+				last = -1
+				curr = *new(T)
+				return false
+			}
+
+			curr, last = h.items[0].item, h.items[0].index
+			return true
+		},
+		get: func() (T, error) {
+			if err != nil {
+				return *new(T), err
+			}
+			return curr, nil
+		},
+		close: func() error {
+			var err error
+			for i := range iters {
+				if closeErr := iters[i].Close(); err == nil && closeErr != nil {
+					err = closeErr
+				}
+			}
+			return err
+		},
+		err: func() error {
+			return err
+		},
+	}
 }
@@ -1,7 +1,14 @@
 package iterator
 
 import (
+	"context"
+	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMerge(t *testing.T) {
@@ -12,3 +19,68 @@ func TestMerge(t *testing.T) {
 	merged := Merge(a, b, c)
 	checkIteratorEqualUnordered(t, merged, []int{1, 2, 3, 4, 5, 6, 7, 8, 9})
 }
+
+func TestMergeSorted(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	cases := []struct {
+		iter     Iterator[int]
+		expected []int
+	}{
+		{MergeSorted[int](less), []int{}},
+		{MergeSorted(less, Empty[int](), FromSlice([]int{1, 2})), []int{1, 2}},
+		{MergeSorted(less, FromSlice([]int{1, 4, 7}), FromSlice([]int{2, 5, 8}), FromSlice([]int{3, 6, 9})), []int{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{MergeSorted(less, FromSlice([]int{1, 1, 3}), FromSlice([]int{1, 2, 3})), []int{1, 1, 1, 2, 3, 3}},
+	}
+
+	for i := range cases {
+		checkIteratorEqual(t, cases[i].iter, cases[i].expected)
+	}
+}
+
+func TestMergeSortedStable(t *testing.T) {
+	less := func(a, b KV[int, string]) bool { return a.Key < b.Key }
+	iter := MergeSorted(less,
+		FromSlice([]KV[int, string]{{1, "a"}, {2, "a"}}),
+		FromSlice([]KV[int, string]{{1, "b"}, {2, "b"}}),
+	)
+	checkIteratorEqual(t, iter, []KV[int, string]{{1, "a"}, {1, "b"}, {2, "a"}, {2, "b"}})
+}
+
+func TestMergeSortedError(t *testing.T) {
+	errTest := errors.New("test error")
+	var closed atomic.Int32
+	failing := Map(func(_ int, item int) (int, error) {
+		if item == 4 {
+			return 0, errTest
+		}
+		return item, nil
+	})(FromSlice([]int{2, 4, 6}))
+
+	iter := MergeSorted(func(a, b int) bool { return a < b },
+		closeTracker(FromSlice([]int{1, 3, 5}), &closed),
+		closeTracker(failing, &closed),
+	)
+	items, err := ToSlice(iter)
+	require.ErrorIs(t, err, errTest)
+	assert.Nil(t, items)
+	require.NoError(t, iter.Close())
+	assert.EqualValues(t, 2, closed.Load())
+}
+
+func TestMergeContextCancelWhileWaiting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	slow := FromFunc(func() (int, bool, error) {
+		<-ctx.Done()
+		return 0, false, nil
+	})
+	merged := MergeContext(ctx, slow)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	for merged.Next() {
+	}
+	assert.ErrorIs(t, merged.Err(), context.Canceled)
+	assert.Nil(t, merged.Close())
+}
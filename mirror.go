@@ -1,69 +1,84 @@
 package iterator
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/wlMalk/iterator/internal/buffer"
 )
 
-// Mirror creates multiple synchronised iterators with the same underlying iterator
-func Mirror[T any](iter Iterator[T], count int) []Iterator[T] {
+// Mirror creates multiple synchronised iterators with the same underlying iterator.
+// The underlying iterator is closed once it is exhausted or all mirrors are closed.
+// Items not yet consumed by a mirror are buffered, opts control how they are buffered.
+func Mirror[T any](iter Iterator[T], count int, opts ...BufferOption[T]) []Iterator[T] {
+	config := newBufferConfig(opts)
 	nextChan := make(chan *buffer.Iterator[T])
 	closeChan := make(chan *buffer.Iterator[T])
+	done := buffer.NewDone()
 
 	buffers := make([]*buffer.Buffer[T], count)
 	mirrors := make([]Iterator[T], count)
 
 	for i := range buffers {
-		buffers[i] = buffer.New[T]()
-		mirrors[i] = buffer.NewIterator(buffers[i], nextChan, closeChan)
+		buffers[i] = config.newBuffer()
+		mirrors[i] = buffer.NewIterator(buffers[i], nextChan, closeChan, done)
 	}
 
 	go func() {
-		var finished bool
-		var err error
 		var closedCount int
 
 		for {
 			select {
 			case curr := <-nextChan:
-				item, ok := curr.Buffer.Pop()
-				if ok {
-					curr.SendItem(item)
-					continue
-				}
-
-				if finished {
-					curr.End()
-					continue
-				}
-
+				item, ok, err := curr.Buffer.Pop()
 				if err != nil {
+					iter.Close()
 					curr.SendErr(err)
+					done.Close(err)
+					return
+				}
+				if ok {
+					curr.SendItem(item)
 					continue
 				}
 
 				hasMore := iter.Next()
 				if !hasMore {
-					if err = iter.Err(); err != nil {
+					err := iter.Err()
+					closeErr := iter.Close()
+					if err == nil {
+						err = closeErr
+					}
+					if err != nil {
 						curr.SendErr(err)
-						continue
+					} else {
+						curr.End()
 					}
-
-					finished = true
-					curr.End()
-					continue
+					done.Close(err)
+					return
 				}
 
-				item, err := iter.Get()
+				item, err = iter.Get()
 				if err != nil {
+					iter.Close()
 					curr.SendErr(err)
-					continue
+					done.Close(err)
+					return
 				}
 
 				for _, b := range buffers {
-					if b == curr.Buffer || b.IsClosed() {
+					if b == curr.Buffer {
 						continue
 					}
-					b.Push(item)
+					if err = b.Push(item); err != nil {
+						break
+					}
+				}
+				if err != nil {
+					iter.Close()
+					curr.SendErr(err)
+					done.Close(err)
+					return
 				}
 
 				curr.SendItem(item)
@@ -71,22 +86,301 @@ func Mirror[T any](iter Iterator[T], count int) []Iterator[T] {
 			case curr := <-closeChan:
 				closedCount++
 
-				if curr.Buffer.IsEmpty() {
-					err = iter.Close()
-					finished = true
-					curr.SendErr(err)
-				} else {
+				if closedCount < count {
 					curr.SendErr(nil)
+					continue
 				}
 
-				if closedCount == count {
-					close(nextChan)
-					close(closeChan)
-					return
-				}
+				err := iter.Close()
+				curr.SendErr(err)
+				done.Close(err)
+				return
 			}
 		}
 	}()
 
 	return mirrors
 }
+
+// LagPolicy decides what MirrorBounded does when a mirror falls too far behind
+type LagPolicy int
+
+const (
+	// LagBlock makes the faster mirrors wait for the slowest one
+	LagBlock LagPolicy = iota
+	// LagDrop skips items for the slowest mirror so that it stays within the allowed lag
+	LagDrop
+	// LagFail stops the slowest mirror with a *LagError
+	LagFail
+)
+
+// LagError is reported by a mirror that fell more than the allowed lag behind
+type LagError struct {
+	Mirror int
+	Lag    int
+}
+
+func (e *LagError) Error() string {
+	return fmt.Sprintf("iterator: mirror %d fell %d items behind", e.Mirror, e.Lag)
+}
+
+// MirrorMetrics reports the state of the mirrors created by MirrorBounded
+type MirrorMetrics struct {
+	m interface {
+		lag(int) int
+		dropped(int) int
+		count() int
+	}
+}
+
+// Lag returns the number of items read from the source that mirror i has not consumed yet
+func (m *MirrorMetrics) Lag(i int) int { return m.m.lag(i) }
+
+// Dropped returns the number of items skipped for mirror i by LagDrop
+func (m *MirrorMetrics) Dropped(i int) int { return m.m.dropped(i) }
+
+// Lags returns the current lag of each mirror
+func (m *MirrorMetrics) Lags() []int {
+	lags := make([]int, m.m.count())
+	for i := range lags {
+		lags[i] = m.Lag(i)
+	}
+	return lags
+}
+
+// MirrorBounded is like Mirror but holds at most maxLag items for any mirror.
+// Once a mirror falls more than maxLag items behind the fastest one policy decides
+// whether the faster mirrors block, the slow one skips items, or the slow one fails.
+// maxLag is at least 1. With LagBlock all mirrors must be consumed concurrently.
+func MirrorBounded[T any](iter Iterator[T], count int, maxLag int, policy LagPolicy) ([]Iterator[T], *MirrorMetrics) {
+	if maxLag < 1 {
+		maxLag = 1
+	}
+	m := &boundedMirror[T]{
+		source:   iter,
+		maxLag:   maxLag,
+		policy:   policy,
+		pos:      make([]int, count),
+		drops:    make([]int, count),
+		detached: make([]bool, count),
+		errs:     make([]error, count),
+	}
+	m.cond = sync.NewCond(&m.lock)
+
+	mirrors := make([]Iterator[T], count)
+	for i := range mirrors {
+		mirrors[i] = &boundedMirrorIterator[T]{m: m, id: i}
+	}
+
+	return mirrors, &MirrorMetrics{m: m}
+}
+
+type boundedMirror[T any] struct {
+	source Iterator[T]
+	maxLag int
+	policy LagPolicy
+
+	lock sync.Mutex
+	cond *sync.Cond
+
+	// items holds the items at positions [base, head)
+	items    []T
+	base     int
+	head     int
+	pos      []int
+	drops    []int
+	detached []bool
+	errs     []error
+
+	fetching    bool
+	finished    bool
+	err         error
+	closedCount int
+	closeErr    error
+}
+
+type boundedMirrorIterator[T any] struct {
+	m  *boundedMirror[T]
+	id int
+
+	curr   T
+	err    error
+	closed bool
+}
+
+func (m *boundedMirror[T]) count() int { return len(m.pos) }
+
+func (m *boundedMirror[T]) lag(i int) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.detached[i] {
+		return 0
+	}
+	return m.head - m.pos[i]
+}
+
+func (m *boundedMirror[T]) dropped(i int) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.drops[i]
+}
+
+// trim drops the items consumed by all mirrors. It must be called with the lock held.
+func (m *boundedMirror[T]) trim() {
+	min := m.head
+	for i := range m.pos {
+		if !m.detached[i] && m.pos[i] < min {
+			min = m.pos[i]
+		}
+	}
+	if min > m.base {
+		var zero T
+		for i := 0; i < min-m.base; i++ {
+			m.items[i] = zero
+		}
+		m.items = m.items[min-m.base:]
+		m.base = min
+	}
+}
+
+// blocked reports whether reading a new item would leave a mirror other than id
+// more than maxLag items behind. It must be called with the lock held.
+func (m *boundedMirror[T]) blocked(id int) bool {
+	for i := range m.pos {
+		if i != id && !m.detached[i] && m.head+1-m.pos[i] > m.maxLag {
+			return true
+		}
+	}
+	return false
+}
+
+// enforceLag applies the lag policy after a new item is read.
+// It must be called with the lock held.
+func (m *boundedMirror[T]) enforceLag() {
+	for i := range m.pos {
+		lag := m.head - m.pos[i]
+		if m.detached[i] || lag <= m.maxLag {
+			continue
+		}
+
+		switch m.policy {
+		case LagDrop:
+			m.pos[i] += lag - m.maxLag
+			m.drops[i] += lag - m.maxLag
+		case LagFail:
+			m.errs[i] = &LagError{Mirror: i, Lag: lag}
+			m.detached[i] = true
+		}
+	}
+	m.trim()
+}
+
+func (m *boundedMirror[T]) next(id int) (T, bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for {
+		if m.errs[id] != nil {
+			return *new(T), false, m.errs[id]
+		}
+		if m.detached[id] {
+			return *new(T), false, nil
+		}
+
+		if m.pos[id] < m.head {
+			item := m.items[m.pos[id]-m.base]
+			m.pos[id]++
+			m.trim()
+			m.cond.Broadcast()
+			return item, true, nil
+		}
+
+		if m.finished {
+			return *new(T), false, m.err
+		}
+
+		if m.fetching || (m.policy == LagBlock && m.blocked(id)) {
+			m.cond.Wait()
+			continue
+		}
+
+		m.fetching = true
+		m.lock.Unlock()
+		item, hasMore, err := m.fetch()
+		m.lock.Lock()
+		m.fetching = false
+		m.cond.Broadcast()
+
+		if !hasMore {
+			m.finished = true
+			m.err = err
+			continue
+		}
+
+		m.items = append(m.items, item)
+		m.head++
+		m.enforceLag()
+	}
+}
+
+func (m *boundedMirror[T]) fetch() (T, bool, error) {
+	if !m.source.Next() {
+		err := m.source.Err()
+		closeErr := m.source.Close()
+		if err == nil {
+			err = closeErr
+		}
+		return *new(T), false, err
+	}
+
+	item, err := m.source.Get()
+	if err != nil {
+		m.source.Close()
+		return *new(T), false, err
+	}
+	return item, true, nil
+}
+
+func (m *boundedMirror[T]) close(id int) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.detached[id] = true
+	m.closedCount++
+	m.trim()
+	m.cond.Broadcast()
+
+	if m.closedCount < len(m.pos) {
+		return nil
+	}
+
+	for m.fetching {
+		m.cond.Wait()
+	}
+	if !m.finished {
+		m.finished = true
+		m.closeErr = m.source.Close()
+	}
+	return m.closeErr
+}
+
+func (iter *boundedMirrorIterator[T]) Next() bool {
+	if iter.closed || iter.err != nil {
+		return false
+	}
+
+	var ok bool
+	iter.curr, ok, iter.err = iter.m.next(iter.id)
+	return ok
+}
+
+func (iter *boundedMirrorIterator[T]) Get() (T, error) { return iter.curr, iter.err }
+func (iter *boundedMirrorIterator[T]) Err() error      { return iter.err }
+
+func (iter *boundedMirrorIterator[T]) Close() error {
+	if iter.closed {
+		return nil
+	}
+	iter.closed = true
+	return iter.m.close(iter.id)
+}
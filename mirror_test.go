@@ -2,7 +2,11 @@ package iterator
 
 import (
 	"sync"
+	"sync/atomic"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMirror(t *testing.T) {
@@ -21,3 +25,48 @@ func TestMirror(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestMirrorBoundedBlock(t *testing.T) {
+	mirrors, metrics := MirrorBounded(Range(1, 100, 1), 3, 2, LagBlock)
+
+	var wg sync.WaitGroup
+	wg.Add(len(mirrors))
+	for i := range mirrors {
+		go func(i int) {
+			defer wg.Done()
+			var items []int
+			_, err := Iterate(mirrors[i], func(_ int, item int) (bool, error) {
+				items = append(items, item)
+				for _, lag := range metrics.Lags() {
+					assert.LessOrEqual(t, lag, 2)
+				}
+				return true, nil
+			})
+			assert.NoError(t, err)
+			assert.Len(t, items, 100)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestMirrorBoundedDrop(t *testing.T) {
+	var closed atomic.Int32
+	mirrors, metrics := MirrorBounded(closeTracker(Range(1, 10, 1), &closed), 2, 3, LagDrop)
+
+	checkIteratorEqual(t, mirrors[0], []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	assert.Equal(t, 3, metrics.Lag(1))
+	assert.Equal(t, 7, metrics.Dropped(1))
+	assert.EqualValues(t, 1, closed.Load())
+	checkIteratorEqual(t, mirrors[1], []int{8, 9, 10})
+}
+
+func TestMirrorBoundedFail(t *testing.T) {
+	mirrors, _ := MirrorBounded(Range(1, 10, 1), 2, 3, LagFail)
+
+	checkIteratorEqual(t, mirrors[0], []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	_, err := ToSlice(mirrors[1])
+	var lagErr *LagError
+	require.ErrorAs(t, err, &lagErr)
+	assert.Equal(t, 1, lagErr.Mirror)
+	assert.Equal(t, 4, lagErr.Lag)
+}
@@ -1,5 +1,9 @@
 package iterator
 
+import (
+	"context"
+)
+
 // Pipe applies the modifiers to the given iterator and returns the resulting
 // iterator
 func Pipe[T any](iter Iterator[T], mods ...Modifier[T, T]) Iterator[T] {
@@ -9,6 +13,11 @@ func Pipe[T any](iter Iterator[T], mods ...Modifier[T, T]) Iterator[T] {
 	return iter
 }
 
+// IterateContext is like Iterate but stops with ctx.Err() once ctx is done
+func IterateContext[T any](ctx context.Context, iterator Iterator[T], f func(int, T) (bool, error)) (int, error) {
+	return Iterate(WithContext[T](ctx)(iterator), f)
+}
+
 func Iterate[T any](iterator Iterator[T], f func(int, T) (bool, error)) (int, error) {
 	var count int
 	shouldContinue := true
@@ -35,6 +44,20 @@ func Iterate[T any](iterator Iterator[T], f func(int, T) (bool, error)) (int, er
 	return count, nil
 }
 
+// drain reads all the items of iterator like Iterate but leaves closing it to the caller
+func drain[T any](iterator Iterator[T], f func(int, T) error) error {
+	for i := 0; iterator.Next(); i++ {
+		item, err := iterator.Get()
+		if err != nil {
+			return err
+		}
+		if err := f(i, item); err != nil {
+			return err
+		}
+	}
+	return iterator.Err()
+}
+
 // Len exhausts the iterator to return its length
 func Len[T any](iter Iterator[T]) (int, error) {
 	var count int
@@ -0,0 +1,172 @@
+package iterator
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Clock tells the time to the pacing modifiers, it can be replaced to control time in tests
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+type pacingConfig struct {
+	clock Clock
+	ctx   context.Context
+}
+
+// PacingOption configures the pacing modifiers
+type PacingOption func(*pacingConfig)
+
+// PacingClock makes the pacing modifiers use clock instead of the system clock
+func PacingClock(clock Clock) PacingOption {
+	return func(c *pacingConfig) {
+		c.clock = clock
+	}
+}
+
+// PacingContext makes the pacing modifiers stop with ctx.Err() once ctx is done,
+// including while they wait.
+func PacingContext(ctx context.Context) PacingOption {
+	return func(c *pacingConfig) {
+		c.ctx = ctx
+	}
+}
+
+func newPacingConfig(opts []PacingOption) *pacingConfig {
+	c := &pacingConfig{
+		clock: systemClock{},
+		ctx:   context.Background(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// wait blocks for d or until the context is done
+func (c *pacingConfig) wait(d time.Duration) error {
+	select {
+	case <-c.clock.After(d):
+		return nil
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	}
+}
+
+// RateLimit returns a modifier that emits at most n items per duration using a token bucket
+// holding up to burst tokens, which starts full. Items wait for a token instead of being dropped.
+func RateLimit[T any](n int, per time.Duration, burst int, opts ...PacingOption) Modifier[T, T] {
+	if n < 1 || per <= 0 || burst < 1 {
+		panic("RateLimit: n, per and burst must be greater than zero")
+	}
+	rate := float64(n) / float64(per)
+
+	return func(iter Iterator[T]) Iterator[T] {
+		config := newPacingConfig(opts)
+		tokens := float64(burst)
+		var last time.Time
+
+		return FilterMap(func(i int, item T) (T, bool, error) {
+			for {
+				now := config.clock.Now()
+				if i > 0 {
+					tokens = min(float64(burst), tokens+float64(now.Sub(last))*rate)
+				}
+				last = now
+				if tokens >= 1 {
+					tokens--
+					return item, true, nil
+				}
+				// rounding up keeps the wait from truncating to zero
+				if err := config.wait(time.Duration(math.Ceil((1 - tokens) / rate))); err != nil {
+					return *new(T), false, err
+				}
+			}
+		})(WithContext[T](config.ctx)(iter))
+	}
+}
+
+// Throttle returns a modifier that emits at most one item per interval,
+// skipping the items read before interval has passed since the last emitted one.
+func Throttle[T any](interval time.Duration, opts ...PacingOption) Modifier[T, T] {
+	if interval <= 0 {
+		panic("Throttle: interval must be greater than zero")
+	}
+	return func(iter Iterator[T]) Iterator[T] {
+		config := newPacingConfig(opts)
+		var last time.Time
+		var emitted bool
+
+		return FilterMap(func(_ int, item T) (T, bool, error) {
+			now := config.clock.Now()
+			if emitted && now.Sub(last) < interval {
+				return *new(T), false, nil
+			}
+			last, emitted = now, true
+			return item, true, nil
+		})(WithContext[T](config.ctx)(iter))
+	}
+}
+
+// Debounce returns a modifier that emits an item only once quiet has passed without another item
+// following it, and the last item when the source ends. The source is read in a separate goroutine,
+// closing the result does not wait for a pending Next on the source, which closes it once it returns.
+func Debounce[T any](quiet time.Duration, opts ...PacingOption) Modifier[T, T] {
+	return func(iter Iterator[T]) Iterator[T] {
+		config := newPacingConfig(opts)
+		var items <-chan ValErr[T]
+		var stop func() error
+		var done bool
+		var err error
+
+		return OnClose(FromFunc(func() (T, bool, error) {
+			if items == nil {
+				items, stop = pump(config.ctx, iter)
+			}
+			if done {
+				return *new(T), false, err
+			}
+
+			var latest T
+			var has bool
+			var timer <-chan time.Time
+			for {
+				select {
+				case item, ok := <-items:
+					if !ok || item.Err != nil {
+						done, err = true, item.Err
+						if !ok && config.ctx.Err() != nil {
+							err = config.ctx.Err()
+							return *new(T), false, err
+						}
+						// the error is reported after the pending item
+						if has {
+							return latest, true, nil
+						}
+						return *new(T), false, err
+					}
+					latest, has = item.Val, true
+					timer = config.clock.After(quiet)
+				case <-timer:
+					return latest, true, nil
+				case <-config.ctx.Done():
+					done, err = true, config.ctx.Err()
+					return *new(T), false, err
+				}
+			}
+		}), func() error {
+			if stop != nil {
+				return stop()
+			}
+			return iter.Close()
+		})
+	}
+}
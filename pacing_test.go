@@ -0,0 +1,168 @@
+package iterator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// fakeClock only moves when advanced, or on every After call if auto is set
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	auto    bool
+	waiters []fakeWaiter
+	calls   int
+}
+
+func newFakeClock(auto bool) *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0), auto: auto}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	c.calls++
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	c.mu.Unlock()
+
+	if c.auto {
+		c.Advance(d)
+	}
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	var pending []fakeWaiter
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+func (c *fakeClock) waitCalls(t *testing.T, calls int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.calls >= calls
+	}, time.Second, time.Millisecond)
+}
+
+func TestRateLimit(t *testing.T) {
+	clock := newFakeClock(true)
+	limited := RateLimit[int](2, time.Second, 2, PacingClock(clock))(Range(1, 5, 1))
+
+	var times []time.Duration
+	_, err := Iterate(limited, func(_ int, _ int) (bool, error) {
+		times = append(times, clock.Now().Sub(time.Unix(0, 0)))
+		return true, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{0, 0, 500 * time.Millisecond, time.Second, 1500 * time.Millisecond}, times)
+}
+
+func TestRateLimitContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	limited := RateLimit[int](1, time.Second, 1, PacingClock(newFakeClock(false)), PacingContext(ctx))(Range(1, 5, 1))
+	require.True(t, limited.Next())
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	assert.False(t, limited.Next())
+	assert.ErrorIs(t, limited.Err(), context.Canceled)
+}
+
+func TestThrottle(t *testing.T) {
+	clock := newFakeClock(false)
+	gaps := []time.Duration{0, 100 * time.Millisecond, 300 * time.Millisecond, 200 * time.Millisecond, 600 * time.Millisecond}
+	source := Map(func(i int, item int) (int, error) {
+		clock.Advance(gaps[i])
+		return item, nil
+	})(Range(1, 5, 1))
+
+	checkIteratorEqual(t, Throttle[int](500*time.Millisecond, PacingClock(clock))(source), []int{1, 4, 5})
+	assert.Panics(t, func() { Throttle[int](0) })
+}
+
+func TestDebounce(t *testing.T) {
+	clock := newFakeClock(false)
+	source := make(chan int)
+	debounced := Debounce[int](time.Second, PacingClock(clock))(FromChannel(source))
+
+	results := make(chan int)
+	go func() {
+		defer close(results)
+		for debounced.Next() {
+			item, _ := debounced.Get()
+			results <- item
+		}
+	}()
+
+	source <- 1
+	clock.waitCalls(t, 1)
+	clock.Advance(500 * time.Millisecond)
+	source <- 2
+	clock.waitCalls(t, 2)
+	clock.Advance(500 * time.Millisecond)
+	source <- 3
+	clock.waitCalls(t, 3)
+	clock.Advance(time.Second)
+	assert.Equal(t, 3, <-results)
+
+	source <- 4
+	clock.waitCalls(t, 4)
+	close(source)
+	assert.Equal(t, 4, <-results)
+
+	_, ok := <-results
+	assert.False(t, ok)
+	assert.NoError(t, debounced.Err())
+	assert.NoError(t, debounced.Close())
+}
+
+func TestDebounceCloseIdle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	source := make(chan int)
+	defer close(source)
+	debounced := Debounce[int](time.Second, PacingClock(newFakeClock(false)), PacingContext(ctx))(FromChannel(source))
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	assert.False(t, debounced.Next())
+	assert.ErrorIs(t, debounced.Err(), context.Canceled)
+
+	returned := make(chan error)
+	go func() { returned <- debounced.Close() }()
+	select {
+	case err := <-returned:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		require.FailNow(t, "Close waited for a source blocked in Next")
+	}
+}
@@ -0,0 +1,307 @@
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+type parallelResult[S any] struct {
+	item    S
+	matches bool
+	err     error
+}
+
+type parallelJob[T any, S any] struct {
+	index  int
+	item   T
+	result chan parallelResult[S]
+}
+
+// ParallelMap returns a modifier that applies fn on up to workers items concurrently.
+// Items keep the order of the source iterator. fn must be safe for concurrent use.
+func ParallelMap[T any, S any](workers int, fn func(int, T) (S, error)) Modifier[T, S] {
+	return ParallelFilterMap(workers, func(i int, item T) (S, bool, error) {
+		nItem, err := fn(i, item)
+		if err != nil {
+			return *new(S), false, err
+		}
+		return nItem, true, nil
+	})
+}
+
+// ParallelFilterMap returns a modifier like FilterMap that applies fn on up to workers
+// items concurrently. Items keep the order of the source iterator, and only about
+// 2*workers items are read ahead of the consumer.
+// The first error stops reading from the source. fn must be safe for concurrent use.
+func ParallelFilterMap[T any, S any](workers int, fn func(int, T) (S, bool, error)) Modifier[T, S] {
+	if workers < 1 {
+		workers = 1
+	}
+	return func(iter Iterator[T]) Iterator[S] {
+		ctx, cancel := context.WithCancel(context.Background())
+		jobs := make(chan parallelJob[T, S])
+		order := make(chan chan parallelResult[S], workers)
+
+		var wg sync.WaitGroup
+		wg.Add(workers + 1)
+
+		for w := 0; w < workers; w++ {
+			go func() {
+				defer wg.Done()
+				for job := range jobs {
+					item, matches, err := fn(job.index, job.item)
+					if err != nil {
+						cancel()
+					}
+					job.result <- parallelResult[S]{item: item, matches: matches, err: err}
+				}
+			}()
+		}
+
+		go func() {
+			defer wg.Done()
+			defer close(order)
+			defer close(jobs)
+
+			fail := func(err error) {
+				result := make(chan parallelResult[S], 1)
+				result <- parallelResult[S]{err: err}
+				select {
+				case <-ctx.Done():
+				case order <- result:
+				}
+			}
+
+			for index := 0; ctx.Err() == nil && iter.Next(); index++ {
+				item, err := iter.Get()
+				if err != nil {
+					fail(err)
+					return
+				}
+
+				result := make(chan parallelResult[S], 1)
+				select {
+				case <-ctx.Done():
+					return
+				case order <- result:
+				}
+				select {
+				case <-ctx.Done():
+					result <- parallelResult[S]{err: ctx.Err()}
+					return
+				case jobs <- parallelJob[T, S]{index: index, item: item, result: result}:
+				}
+			}
+
+			if err := iter.Err(); err != nil {
+				fail(err)
+			}
+		}()
+
+		var curr S
+		var err error
+		var closed bool
+		var closeErr error
+
+		return &iterator[S]{
+			next: func() bool {
+				if err != nil || closed {
+					return false
+				}
+				for result := range order {
+					r := <-result
+					if r.err != nil {
+						err = r.err
+						cancel()
+						return false
+					}
+					if r.matches {
+						curr = r.item
+						return true
+					}
+				}
+				return false
+			},
+			get: func() (S, error) {
+				if err != nil {
+					return *new(S), err
+				}
+				return curr, nil
+			},
+			close: func() error {
+				if closed {
+					return closeErr
+				}
+				closed = true
+				cancel()
+				for range order {
+				}
+				wg.Wait()
+				closeErr = iter.Close()
+				return closeErr
+			},
+			err: func() error {
+				return err
+			},
+		}
+	}
+}
+
+// ItemError records an error returned by fn for the item at Index
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("iterator: item %d: %v", e.Index, e.Err)
+}
+
+func (e *ItemError) Unwrap() error { return e.Err }
+
+type parallelConfig struct {
+	continueOnError bool
+}
+
+// ParallelOption configures the parallel modifiers
+type ParallelOption func(*parallelConfig)
+
+// ContinueOnError makes the iterator skip items for which fn fails instead of stopping.
+// The errors are collected as *ItemError values and joined into the error reported
+// by Err once the iterator is exhausted.
+func ContinueOnError() ParallelOption {
+	return func(c *parallelConfig) {
+		c.continueOnError = true
+	}
+}
+
+// ParallelMapUnordered returns a modifier that applies fn on up to workers items concurrently.
+// Each result is emitted as soon as it is ready along with the index of its source item.
+// Unless ContinueOnError is given, the first error stops reading from the source.
+// fn must be safe for concurrent use.
+func ParallelMapUnordered[T any, S any](workers int, fn func(int, T) (S, error), opts ...ParallelOption) Modifier[T, KV[int, S]] {
+	if workers < 1 {
+		workers = 1
+	}
+	var config parallelConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return func(iter Iterator[T]) Iterator[KV[int, S]] {
+		ctx, cancel := context.WithCancel(context.Background())
+		jobs := make(chan KV[int, T])
+		c := make(chan ValErr[KV[int, S]], workers)
+
+		var errsLock sync.Mutex
+		var errs []error
+
+		send := func(item ValErr[KV[int, S]]) {
+			select {
+			case <-ctx.Done():
+			case c <- item:
+			}
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(workers + 1)
+
+		for w := 0; w < workers; w++ {
+			go func() {
+				defer wg.Done()
+				for job := range jobs {
+					item, err := fn(job.Key, job.Val)
+					if err != nil {
+						err = &ItemError{Index: job.Key, Err: err}
+						if config.continueOnError {
+							errsLock.Lock()
+							errs = append(errs, err)
+							errsLock.Unlock()
+							continue
+						}
+						send(ValErr[KV[int, S]]{Err: err})
+						cancel()
+						continue
+					}
+					send(ValErr[KV[int, S]]{Val: KV[int, S]{Key: job.Key, Val: item}})
+				}
+			}()
+		}
+
+		go func() {
+			defer wg.Done()
+			defer close(jobs)
+
+			for index := 0; ctx.Err() == nil && iter.Next(); index++ {
+				item, err := iter.Get()
+				if err != nil {
+					send(ValErr[KV[int, S]]{Err: err})
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case jobs <- KV[int, T]{Key: index, Val: item}:
+				}
+			}
+
+			if err := iter.Err(); err != nil {
+				send(ValErr[KV[int, S]]{Err: err})
+			}
+		}()
+
+		finished := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(c)
+			close(finished)
+		}()
+
+		results := FromValErrChannel(c)
+		var done bool
+		var closed bool
+		var closeErr error
+		var err error
+
+		return &iterator[KV[int, S]]{
+			next: func() bool {
+				if done || closed {
+					return false
+				}
+				if !results.Next() {
+					done = true
+					return false
+				}
+				// the first error ends the iterator
+				if _, err = results.Get(); err != nil {
+					done = true
+					return false
+				}
+				return true
+			},
+			get: results.Get,
+			close: func() error {
+				if closed {
+					return closeErr
+				}
+				closed = true
+				cancel()
+				<-finished
+				closeErr = iter.Close()
+				return closeErr
+			},
+			err: func() error {
+				if err != nil {
+					return err
+				}
+				if done {
+					return errors.Join(errs...)
+				}
+				return nil
+			},
+		}
+	}
+}
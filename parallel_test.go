@@ -0,0 +1,146 @@
+package iterator
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelMap(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	iter := ParallelMap(4, func(i int, item int) (int, error) {
+		curr := running.Add(1)
+		defer running.Add(-1)
+		for {
+			max := maxRunning.Load()
+			if curr <= max || maxRunning.CompareAndSwap(max, curr) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(10-item) * time.Millisecond)
+		return item * 2, nil
+	})(Range(0, 9, 1))
+
+	checkIteratorEqual(t, iter, []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18})
+	assert.LessOrEqual(t, maxRunning.Load(), int32(4))
+	assert.Greater(t, maxRunning.Load(), int32(1))
+}
+
+func TestParallelMapError(t *testing.T) {
+	errTest := errors.New("test error")
+	var closed atomic.Int32
+	iter := ParallelMap(3, func(_ int, item int) (int, error) {
+		if item == 5 {
+			return 0, errTest
+		}
+		return item, nil
+	})(closeTracker(Ascending(0, 1), &closed))
+
+	var items []int
+	_, err := Iterate(iter, func(_ int, item int) (bool, error) {
+		items = append(items, item)
+		return true, nil
+	})
+	require.ErrorIs(t, err, errTest)
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, items)
+
+	require.NoError(t, iter.Close())
+	require.NoError(t, iter.Close())
+	assert.EqualValues(t, 1, closed.Load())
+}
+
+func TestParallelFilterMap(t *testing.T) {
+	iter := ParallelFilterMap(2, func(_ int, item int) (string, bool, error) {
+		return string(rune('a' + item)), item%2 == 0, nil
+	})(Range(0, 6, 1))
+
+	checkIteratorEqual(t, iter, []string{"a", "c", "e", "g"})
+}
+
+func TestParallelMapClose(t *testing.T) {
+	var closed atomic.Int32
+	iter := ParallelMap(4, func(_ int, item int) (int, error) {
+		return item, nil
+	})(closeTracker(Ascending(0, 1), &closed))
+
+	checkIteratorEqual(t, Limit[int](3)(iter), []int{0, 1, 2})
+	assert.EqualValues(t, 1, closed.Load())
+}
+
+func TestParallelMapUnordered(t *testing.T) {
+	iter := ParallelMapUnordered(3, func(i int, item string) (int, error) {
+		return len(item), nil
+	})(FromSlice([]string{"a", "bb", "ccc", "dddd"}))
+
+	checkIteratorEqualUnordered(t, iter, []KV[int, int]{{0, 1}, {1, 2}, {2, 3}, {3, 4}})
+}
+
+func TestParallelMapUnorderedError(t *testing.T) {
+	errTest := errors.New("test error")
+	var closed atomic.Int32
+	iter := ParallelMapUnordered(3, func(_ int, item int) (int, error) {
+		if item == 5 {
+			return 0, errTest
+		}
+		return item, nil
+	})(closeTracker(Ascending(0, 1), &closed))
+
+	_, err := ToSlice(iter)
+	require.ErrorIs(t, err, errTest)
+	var itemErr *ItemError
+	require.ErrorAs(t, err, &itemErr)
+	assert.Equal(t, 5, itemErr.Index)
+
+	require.NoError(t, iter.Close())
+	assert.EqualValues(t, 1, closed.Load())
+}
+
+func TestParallelMapUnorderedErrorSticky(t *testing.T) {
+	errTest := errors.New("test error")
+	iter := ParallelMapUnordered(1, func(_ int, item int) (int, error) {
+		if item == 2 {
+			return 0, errTest
+		}
+		return item, nil
+	})(Range(1, 5, 1))
+
+	var items []int
+	for iter.Next() {
+		item, err := iter.Get()
+		require.NoError(t, err)
+		items = append(items, item.Val)
+	}
+	assert.Equal(t, []int{1}, items)
+	assert.ErrorIs(t, iter.Err(), errTest)
+	assert.False(t, iter.Next())
+	assert.ErrorIs(t, iter.Err(), errTest)
+	require.NoError(t, iter.Close())
+}
+
+func TestParallelMapUnorderedContinueOnError(t *testing.T) {
+	errTest := errors.New("test error")
+	iter := ParallelMapUnordered(2, func(_ int, item int) (int, error) {
+		if item%3 == 0 {
+			return 0, errTest
+		}
+		return item, nil
+	}, ContinueOnError())(Range(0, 6, 1))
+
+	var items []int
+	_, err := Iterate(iter, func(_ int, item KV[int, int]) (bool, error) {
+		items = append(items, item.Val)
+		return true, nil
+	})
+	require.ErrorIs(t, err, errTest)
+	assert.ElementsMatch(t, []int{1, 2, 4, 5}, items)
+
+	var indexes []int
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		indexes = append(indexes, e.(*ItemError).Index)
+	}
+	assert.ElementsMatch(t, []int{0, 3, 6}, indexes)
+}
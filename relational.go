@@ -0,0 +1,381 @@
+package iterator
+
+import (
+	"golang.org/x/exp/constraints"
+)
+
+// Pair holds the items matched by a join.
+// For outer joins the side without a match is the zero value
+// and its HasLeft or HasRight is false.
+type Pair[L any, R any] struct {
+	Left     L
+	Right    R
+	HasLeft  bool
+	HasRight bool
+}
+
+// joinOutput builds the items of a join. both is called for matching items,
+// leftOnly for lefts without a match or, when semi is set, lefts with a match,
+// and rightOnly for rights without a match. A nil func means no such items are emitted.
+type joinOutput[L any, R any, O any] struct {
+	both      func(L, R) O
+	leftOnly  func(L) O
+	rightOnly func(R) O
+	semi      bool
+}
+
+func pairOutput[L any, R any](withLeft, withRight bool) joinOutput[L, R, Pair[L, R]] {
+	out := joinOutput[L, R, Pair[L, R]]{
+		both: func(l L, r R) Pair[L, R] {
+			return Pair[L, R]{Left: l, Right: r, HasLeft: true, HasRight: true}
+		},
+	}
+	if withLeft {
+		out.leftOnly = func(l L) Pair[L, R] { return Pair[L, R]{Left: l, HasLeft: true} }
+	}
+	if withRight {
+		out.rightOnly = func(r R) Pair[L, R] { return Pair[L, R]{Right: r, HasRight: true} }
+	}
+	return out
+}
+
+func leftOutput[L any, R any](semi bool) joinOutput[L, R, L] {
+	return joinOutput[L, R, L]{
+		leftOnly: func(l L) L { return l },
+		semi:     semi,
+	}
+}
+
+// InnerJoin returns pairs of items from left and right with equal keys.
+// Both sides are read alternately until one of them ends, the smaller side is then
+// held in memory and the items follow the order of the other side.
+func InnerJoin[L any, R any, K comparable](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error)) Iterator[Pair[L, R]] {
+	return hashJoin(left, right, leftKey, rightKey, pairOutput[L, R](false, false))
+}
+
+// LeftJoin is like InnerJoin but also returns items from left without a match.
+func LeftJoin[L any, R any, K comparable](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error)) Iterator[Pair[L, R]] {
+	return hashJoin(left, right, leftKey, rightKey, pairOutput[L, R](true, false))
+}
+
+// RightJoin is like InnerJoin but also returns items from right without a match.
+func RightJoin[L any, R any, K comparable](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error)) Iterator[Pair[L, R]] {
+	return hashJoin(left, right, leftKey, rightKey, pairOutput[L, R](false, true))
+}
+
+// FullOuterJoin is like InnerJoin but also returns items from both sides without a match.
+func FullOuterJoin[L any, R any, K comparable](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error)) Iterator[Pair[L, R]] {
+	return hashJoin(left, right, leftKey, rightKey, pairOutput[L, R](true, true))
+}
+
+// SemiJoin returns items from left which have a match in right, each only once.
+func SemiJoin[L any, R any, K comparable](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error)) Iterator[L] {
+	return hashJoin(left, right, leftKey, rightKey, leftOutput[L, R](true))
+}
+
+// AntiJoin returns items from left which have no match in right.
+func AntiJoin[L any, R any, K comparable](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error)) Iterator[L] {
+	return hashJoin(left, right, leftKey, rightKey, leftOutput[L, R](false))
+}
+
+// InnerJoinSorted returns pairs of items from left and right with equal keys.
+// Both sides must be sorted by key in ascending order, they are streamed
+// and only the items of one key from right are held in memory.
+// ErrNotSorted is returned when the keys are out of order.
+func InnerJoinSorted[L any, R any, K constraints.Ordered](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error)) Iterator[Pair[L, R]] {
+	return mergeJoin(left, right, leftKey, rightKey, pairOutput[L, R](false, false))
+}
+
+// LeftJoinSorted is like InnerJoinSorted but also returns items from left without a match.
+func LeftJoinSorted[L any, R any, K constraints.Ordered](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error)) Iterator[Pair[L, R]] {
+	return mergeJoin(left, right, leftKey, rightKey, pairOutput[L, R](true, false))
+}
+
+// RightJoinSorted is like InnerJoinSorted but also returns items from right without a match.
+func RightJoinSorted[L any, R any, K constraints.Ordered](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error)) Iterator[Pair[L, R]] {
+	return mergeJoin(left, right, leftKey, rightKey, pairOutput[L, R](false, true))
+}
+
+// FullOuterJoinSorted is like InnerJoinSorted but also returns items from both sides without a match.
+func FullOuterJoinSorted[L any, R any, K constraints.Ordered](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error)) Iterator[Pair[L, R]] {
+	return mergeJoin(left, right, leftKey, rightKey, pairOutput[L, R](true, true))
+}
+
+// SemiJoinSorted returns items from left which have a match in right, each only once.
+// Both sides must be sorted by key in ascending order.
+func SemiJoinSorted[L any, R any, K constraints.Ordered](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error)) Iterator[L] {
+	return mergeJoin(left, right, leftKey, rightKey, leftOutput[L, R](true))
+}
+
+// AntiJoinSorted returns items from left which have no match in right.
+// Both sides must be sorted by key in ascending order.
+func AntiJoinSorted[L any, R any, K constraints.Ordered](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error)) Iterator[L] {
+	return mergeJoin(left, right, leftKey, rightKey, leftOutput[L, R](false))
+}
+
+func closeBoth[L any, R any](left Iterator[L], right Iterator[R]) func() error {
+	return func() error {
+		if err := left.Close(); err != nil {
+			right.Close()
+			return err
+		}
+		return right.Close()
+	}
+}
+
+// joinSide reads items of one side of a join along with their keys
+type joinSide[T any, K any] struct {
+	iter  Iterator[T]
+	key   func(int, T) (K, error)
+	items []T
+	keys  []K
+	count int
+	done  bool
+}
+
+func (s *joinSide[T, K]) read() error {
+	if s.done {
+		return nil
+	}
+	if !s.iter.Next() {
+		s.done = true
+		return s.iter.Err()
+	}
+
+	item, err := s.iter.Get()
+	if err != nil {
+		return err
+	}
+	key, err := s.key(s.count, item)
+	if err != nil {
+		return err
+	}
+	s.count++
+	s.items = append(s.items, item)
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+func hashJoin[L any, R any, K comparable, O any](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error), out joinOutput[L, R, O]) Iterator[O] {
+	ls := &joinSide[L, K]{iter: left, key: leftKey}
+	rs := &joinSide[R, K]{iter: right, key: rightKey}
+	var joined func() (O, bool, error)
+
+	return OnClose(FromFunc(func() (O, bool, error) {
+		if joined == nil {
+			for !ls.done && !rs.done {
+				if err := ls.read(); err != nil {
+					return *new(O), false, err
+				}
+				if err := rs.read(); err != nil {
+					return *new(O), false, err
+				}
+			}
+
+			if ls.done && (!rs.done || len(ls.items) <= len(rs.items)) {
+				joined = newHashJoiner(ls, rs, out.both, out.leftOnly, out.rightOnly, out.semi, false).next
+			} else {
+				var both func(R, L) O
+				if out.both != nil {
+					both = func(r R, l L) O { return out.both(l, r) }
+				}
+				joined = newHashJoiner(rs, ls, both, out.rightOnly, out.leftOnly, false, out.semi).next
+			}
+		}
+
+		return joined()
+	}), closeBoth(left, right))
+}
+
+// hashJoiner holds the build side in memory and streams the probe side
+type hashJoiner[B any, P any, K comparable, O any] struct {
+	build *joinSide[B, K]
+	probe *joinSide[P, K]
+	index map[K][]int
+
+	both      func(B, P) O
+	buildOnly func(B) O
+	probeOnly func(P) O
+	// buildSemi and probeSemi make buildOnly and probeOnly emit matched items instead
+	buildSemi bool
+	probeSemi bool
+
+	matched []bool
+	pending []O
+	probed  int
+	tail    int
+}
+
+func newHashJoiner[B any, P any, K comparable, O any](build *joinSide[B, K], probe *joinSide[P, K], both func(B, P) O, buildOnly func(B) O, probeOnly func(P) O, buildSemi, probeSemi bool) *hashJoiner[B, P, K, O] {
+	index := make(map[K][]int)
+	for i, key := range build.keys {
+		index[key] = append(index[key], i)
+	}
+	build.keys = nil
+
+	return &hashJoiner[B, P, K, O]{
+		build:     build,
+		probe:     probe,
+		index:     index,
+		both:      both,
+		buildOnly: buildOnly,
+		probeOnly: probeOnly,
+		buildSemi: buildSemi,
+		probeSemi: probeSemi,
+		matched:   make([]bool, len(build.items)),
+	}
+}
+
+func (j *hashJoiner[B, P, K, O]) next() (O, bool, error) {
+	for len(j.pending) == 0 {
+		if j.probed == len(j.probe.items) && !j.probe.done {
+			j.probe.items, j.probe.keys, j.probed = j.probe.items[:0], j.probe.keys[:0], 0
+			if err := j.probe.read(); err != nil {
+				return *new(O), false, err
+			}
+		}
+
+		if j.probed < len(j.probe.items) {
+			item, key := j.probe.items[j.probed], j.probe.keys[j.probed]
+			j.probe.items[j.probed] = *new(P)
+			j.probed++
+			j.match(item, key)
+			continue
+		}
+
+		if j.tail == len(j.build.items) {
+			return *new(O), false, nil
+		}
+		if j.buildOnly != nil && !j.buildSemi && !j.matched[j.tail] {
+			j.pending = append(j.pending, j.buildOnly(j.build.items[j.tail]))
+		}
+		j.tail++
+	}
+
+	item := j.pending[0]
+	j.pending = j.pending[1:]
+	return item, true, nil
+}
+
+func (j *hashJoiner[B, P, K, O]) match(item P, key K) {
+	indexes := j.index[key]
+	if len(indexes) == 0 {
+		if j.probeOnly != nil && !j.probeSemi {
+			j.pending = append(j.pending, j.probeOnly(item))
+		}
+		return
+	}
+
+	if j.probeOnly != nil && j.probeSemi {
+		j.pending = append(j.pending, j.probeOnly(item))
+	}
+	for _, i := range indexes {
+		if j.both != nil {
+			j.pending = append(j.pending, j.both(j.build.items[i], item))
+		}
+		if j.buildOnly != nil && j.buildSemi && !j.matched[i] {
+			j.pending = append(j.pending, j.buildOnly(j.build.items[i]))
+		}
+		j.matched[i] = true
+	}
+}
+
+func mergeJoin[L any, R any, K constraints.Ordered, O any](left Iterator[L], right Iterator[R], leftKey func(int, L) (K, error), rightKey func(int, R) (K, error), out joinOutput[L, R, O]) Iterator[O] {
+	ls := &joinSide[L, K]{iter: left, key: leftKey}
+	rs := &joinSide[R, K]{iter: right, key: rightKey}
+
+	var started bool
+	var run []R
+	var runKey K
+	var pending []O
+
+	advanceLeft := func() error {
+		return advanceSorted(ls)
+	}
+	advanceRight := func() error {
+		return advanceSorted(rs)
+	}
+
+	return OnClose(FromFunc(func() (O, bool, error) {
+		if !started {
+			started = true
+			if err := advanceLeft(); err != nil {
+				return *new(O), false, err
+			}
+			if err := advanceRight(); err != nil {
+				return *new(O), false, err
+			}
+		}
+
+		for len(pending) == 0 {
+			hasLeft, hasRight := len(ls.items) > 0, len(rs.items) > 0
+
+			if run != nil && hasLeft && ls.keys[0] == runKey {
+				if out.both != nil {
+					for _, r := range run {
+						pending = append(pending, out.both(ls.items[0], r))
+					}
+				}
+				if out.leftOnly != nil && out.semi {
+					pending = append(pending, out.leftOnly(ls.items[0]))
+				}
+				if err := advanceLeft(); err != nil {
+					return *new(O), false, err
+				}
+				continue
+			}
+			run = nil
+
+			switch {
+			case !hasLeft && !hasRight:
+				return *new(O), false, nil
+			case hasLeft && (!hasRight || ls.keys[0] < rs.keys[0]):
+				if out.leftOnly != nil && !out.semi {
+					pending = append(pending, out.leftOnly(ls.items[0]))
+				}
+				if err := advanceLeft(); err != nil {
+					return *new(O), false, err
+				}
+			case hasRight && (!hasLeft || rs.keys[0] < ls.keys[0]):
+				if out.rightOnly != nil {
+					pending = append(pending, out.rightOnly(rs.items[0]))
+				}
+				if err := advanceRight(); err != nil {
+					return *new(O), false, err
+				}
+			default:
+				runKey = rs.keys[0]
+				run = []R{}
+				for len(rs.items) > 0 && rs.keys[0] == runKey {
+					run = append(run, rs.items[0])
+					if err := advanceRight(); err != nil {
+						return *new(O), false, err
+					}
+				}
+			}
+		}
+
+		item := pending[0]
+		pending = pending[1:]
+		return item, true, nil
+	}), closeBoth(left, right))
+}
+
+// advanceSorted replaces the current item of a side with the next one
+// and checks that keys do not decrease.
+func advanceSorted[T any, K constraints.Ordered](s *joinSide[T, K]) error {
+	var last K
+	hadItem := len(s.items) > 0
+	if hadItem {
+		last = s.keys[0]
+	}
+	s.items, s.keys = s.items[:0], s.keys[:0]
+
+	if err := s.read(); err != nil {
+		return err
+	}
+	if hadItem && len(s.keys) > 0 && s.keys[0] < last {
+		return ErrNotSorted
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package iterator
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func joinKey(_ int, item KV[int, string]) (int, error) {
+	return item.Key, nil
+}
+
+func joinLeft() Iterator[KV[int, string]] {
+	return FromSlice([]KV[int, string]{{1, "a"}, {2, "b"}, {3, "c"}, {5, "e"}})
+}
+
+func joinRight() Iterator[KV[int, string]] {
+	return FromSlice([]KV[int, string]{{1, "x"}, {3, "y"}, {3, "z"}, {4, "w"}})
+}
+
+func joinPair(l, r KV[int, string], hasLeft, hasRight bool) Pair[KV[int, string], KV[int, string]] {
+	return Pair[KV[int, string], KV[int, string]]{Left: l, Right: r, HasLeft: hasLeft, HasRight: hasRight}
+}
+
+func TestJoins(t *testing.T) {
+	inner := []Pair[KV[int, string], KV[int, string]]{
+		joinPair(KV[int, string]{1, "a"}, KV[int, string]{1, "x"}, true, true),
+		joinPair(KV[int, string]{3, "c"}, KV[int, string]{3, "y"}, true, true),
+		joinPair(KV[int, string]{3, "c"}, KV[int, string]{3, "z"}, true, true),
+	}
+	leftOnly := []Pair[KV[int, string], KV[int, string]]{
+		joinPair(KV[int, string]{2, "b"}, KV[int, string]{}, true, false),
+		joinPair(KV[int, string]{5, "e"}, KV[int, string]{}, true, false),
+	}
+	rightOnly := []Pair[KV[int, string], KV[int, string]]{
+		joinPair(KV[int, string]{}, KV[int, string]{4, "w"}, false, true),
+	}
+
+	concat := func(parts ...[]Pair[KV[int, string], KV[int, string]]) []Pair[KV[int, string], KV[int, string]] {
+		var all []Pair[KV[int, string], KV[int, string]]
+		for _, part := range parts {
+			all = append(all, part...)
+		}
+		return all
+	}
+
+	checkIteratorEqualUnordered(t, InnerJoin(joinLeft(), joinRight(), joinKey, joinKey), inner)
+	checkIteratorEqualUnordered(t, LeftJoin(joinLeft(), joinRight(), joinKey, joinKey), concat(inner, leftOnly))
+	checkIteratorEqualUnordered(t, RightJoin(joinLeft(), joinRight(), joinKey, joinKey), concat(inner, rightOnly))
+	checkIteratorEqualUnordered(t, FullOuterJoin(joinLeft(), joinRight(), joinKey, joinKey), concat(inner, leftOnly, rightOnly))
+	checkIteratorEqualUnordered(t, SemiJoin(joinLeft(), joinRight(), joinKey, joinKey), []KV[int, string]{{1, "a"}, {3, "c"}})
+	checkIteratorEqualUnordered(t, AntiJoin(joinLeft(), joinRight(), joinKey, joinKey), []KV[int, string]{{2, "b"}, {5, "e"}})
+
+	// swapped sides build the other side in memory
+	checkIteratorEqualUnordered(t, SemiJoin(joinRight(), joinLeft(), joinKey, joinKey), []KV[int, string]{{1, "x"}, {3, "y"}, {3, "z"}})
+	checkIteratorEqualUnordered(t, AntiJoin(joinRight(), joinLeft(), joinKey, joinKey), []KV[int, string]{{4, "w"}})
+
+	checkIteratorEqual(t, InnerJoinSorted(joinLeft(), joinRight(), joinKey, joinKey), inner)
+	checkIteratorEqual(t, LeftJoinSorted(joinLeft(), joinRight(), joinKey, joinKey), []Pair[KV[int, string], KV[int, string]]{
+		inner[0], leftOnly[0], inner[1], inner[2], leftOnly[1],
+	})
+	checkIteratorEqual(t, RightJoinSorted(joinLeft(), joinRight(), joinKey, joinKey), concat(inner, rightOnly))
+	checkIteratorEqual(t, FullOuterJoinSorted(joinLeft(), joinRight(), joinKey, joinKey), []Pair[KV[int, string], KV[int, string]]{
+		inner[0], leftOnly[0], inner[1], inner[2], rightOnly[0], leftOnly[1],
+	})
+	checkIteratorEqual(t, SemiJoinSorted(joinLeft(), joinRight(), joinKey, joinKey), []KV[int, string]{{1, "a"}, {3, "c"}})
+	checkIteratorEqual(t, AntiJoinSorted(joinLeft(), joinRight(), joinKey, joinKey), []KV[int, string]{{2, "b"}, {5, "e"}})
+}
+
+func TestJoinDuplicateKeys(t *testing.T) {
+	left := FromSlice([]KV[int, string]{{1, "a"}, {1, "b"}, {2, "c"}})
+	right := FromSlice([]KV[int, string]{{1, "x"}, {1, "y"}})
+
+	pairs, err := ToSlice(InnerJoinSorted(left, right, joinKey, joinKey))
+	require.NoError(t, err)
+	assert.Equal(t, []Pair[KV[int, string], KV[int, string]]{
+		joinPair(KV[int, string]{1, "a"}, KV[int, string]{1, "x"}, true, true),
+		joinPair(KV[int, string]{1, "a"}, KV[int, string]{1, "y"}, true, true),
+		joinPair(KV[int, string]{1, "b"}, KV[int, string]{1, "x"}, true, true),
+		joinPair(KV[int, string]{1, "b"}, KV[int, string]{1, "y"}, true, true),
+	}, pairs)
+
+	left = FromSlice([]KV[int, string]{{1, "a"}, {1, "b"}, {2, "c"}})
+	right = FromSlice([]KV[int, string]{{1, "x"}, {1, "y"}})
+	checkIteratorEqualUnordered(t, SemiJoin(left, right, joinKey, joinKey), []KV[int, string]{{1, "a"}, {1, "b"}})
+}
+
+func TestJoinNotSorted(t *testing.T) {
+	left := FromSlice([]KV[int, string]{{2, "a"}, {1, "b"}})
+	right := FromSlice([]KV[int, string]{{1, "x"}})
+
+	_, err := ToSlice(InnerJoinSorted(left, right, joinKey, joinKey))
+	assert.ErrorIs(t, err, ErrNotSorted)
+}
+
+func TestJoinErrors(t *testing.T) {
+	keyErr := errors.New("key error")
+	failingKey := func(i int, item KV[int, string]) (int, error) {
+		if i == 1 {
+			return 0, keyErr
+		}
+		return item.Key, nil
+	}
+
+	_, err := ToSlice(InnerJoin(joinLeft(), joinRight(), joinKey, failingKey))
+	assert.ErrorIs(t, err, keyErr)
+
+	_, err = ToSlice(FullOuterJoinSorted(joinLeft(), joinRight(), failingKey, joinKey))
+	assert.ErrorIs(t, err, keyErr)
+}
+
+func TestJoinClose(t *testing.T) {
+	var closed atomic.Int32
+	joined := InnerJoin(closeTracker(joinLeft(), &closed), closeTracker(joinRight(), &closed), joinKey, joinKey)
+	require.True(t, joined.Next())
+	require.NoError(t, joined.Close())
+	assert.Equal(t, int32(2), closed.Load())
+
+	closed.Store(0)
+	joined = InnerJoinSorted(closeTracker(joinLeft(), &closed), closeTracker(joinRight(), &closed), joinKey, joinKey)
+	require.True(t, joined.Next())
+	require.NoError(t, joined.Close())
+	assert.Equal(t, int32(2), closed.Load())
+}
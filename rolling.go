@@ -0,0 +1,109 @@
+package iterator
+
+import (
+	"golang.org/x/exp/constraints"
+)
+
+// MovingSum returns a modifier that emits the sum of each window of size items,
+// starting once the first window is full.
+func MovingSum[T constraints.Float | constraints.Integer](size int) Modifier[T, T] {
+	if size < 1 {
+		panic("MovingSum: size cannot be less than one")
+	}
+	return func(iter Iterator[T]) Iterator[T] {
+		window := make([]T, size)
+		var sum, compensation T
+		// add keeps a Neumaier compensated sum so that large items leaving the
+		// window do not wipe out the small ones still in it
+		add := func(x T) {
+			t := sum + x
+			if abs(sum) >= abs(x) {
+				compensation += (sum - t) + x
+			} else {
+				compensation += (x - t) + sum
+			}
+			sum = t
+		}
+		return FilterMap(func(i int, item T) (T, bool, error) {
+			add(item)
+			add(-window[i%size])
+			window[i%size] = item
+			return sum + compensation, i >= size-1, nil
+		})(iter)
+	}
+}
+
+func abs[T constraints.Float | constraints.Integer](x T) T {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// MovingAverage returns a modifier that emits the mean of each window of size items,
+// starting once the first window is full.
+func MovingAverage[T constraints.Float | constraints.Integer](size int) Modifier[T, float64] {
+	if size < 1 {
+		panic("MovingAverage: size cannot be less than one")
+	}
+	return func(iter Iterator[T]) Iterator[float64] {
+		return Map(func(_ int, sum T) (float64, error) {
+			return float64(sum) / float64(size), nil
+		})(MovingSum[T](size)(iter))
+	}
+}
+
+// EMA returns a modifier that emits the exponential moving average of the items,
+// weighting each new item by alpha. The first item is emitted as is.
+func EMA[T constraints.Float | constraints.Integer](alpha float64) Modifier[T, float64] {
+	if alpha <= 0 || alpha > 1 {
+		panic("EMA: alpha must be greater than zero and at most one")
+	}
+	return func(iter Iterator[T]) Iterator[float64] {
+		var average float64
+		return Map(func(i int, item T) (float64, error) {
+			if i == 0 {
+				average = float64(item)
+			} else {
+				average += alpha * (float64(item) - average)
+			}
+			return average, nil
+		})(iter)
+	}
+}
+
+// RollingMin returns a modifier that emits the smallest item of each window of size items,
+// starting once the first window is full.
+func RollingMin[T constraints.Ordered](size int) Modifier[T, T] {
+	return rolling[T](size, false)
+}
+
+// RollingMax returns a modifier that emits the largest item of each window of size items,
+// starting once the first window is full.
+func RollingMax[T constraints.Ordered](size int) Modifier[T, T] {
+	return rolling[T](size, true)
+}
+
+func rolling[T constraints.Ordered](size int, largest bool) Modifier[T, T] {
+	if size < 1 {
+		panic("RollingMin, RollingMax: size cannot be less than one")
+	}
+	return func(iter Iterator[T]) Iterator[T] {
+		// deque holds the candidates of the window, their items ordered from the extreme one
+		var deque []KV[int, T]
+		return FilterMap(func(i int, item T) (T, bool, error) {
+			for len(deque) > 0 {
+				last := deque[len(deque)-1].Val
+				if (largest && last > item) || (!largest && last < item) {
+					break
+				}
+				deque = deque[:len(deque)-1]
+			}
+			deque = append(deque, KV[int, T]{Key: i, Val: item})
+			if deque[0].Key <= i-size {
+				deque = deque[1:]
+			}
+			return deque[0].Val, i >= size-1, nil
+		})(iter)
+	}
+}
@@ -0,0 +1,34 @@
+package iterator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMovingSum(t *testing.T) {
+	checkIteratorEqual(t, MovingSum[int](3)(Range(1, 6, 1)), []int{6, 9, 12, 15})
+	checkIteratorEqual(t, MovingSum[int](1)(Range(1, 3, 1)), []int{1, 2, 3})
+	checkIteratorEqual(t, MovingSum[int](4)(Range(1, 3, 1)), []int{})
+	checkIteratorEqual(t, MovingSum[float64](2)(FromSlice([]float64{1e20, 1, 1, 1})), []float64{1e20, 2, 2})
+	checkIteratorEqual(t, MovingSum[uint](2)(FromSlice([]uint{3, 1, 4})), []uint{4, 5})
+	assert.Panics(t, func() { MovingSum[int](0) })
+}
+
+func TestMovingAverage(t *testing.T) {
+	checkIteratorEqual(t, MovingAverage[int](2)(FromSlice([]int{1, 2, 4, 8})), []float64{1.5, 3, 6})
+	checkIteratorEqual(t, MovingAverage[float64](2)(FromSlice([]float64{1e20, 1, 1})), []float64{5e19, 1})
+}
+
+func TestEMA(t *testing.T) {
+	checkIteratorEqual(t, EMA[float64](0.5)(FromSlice([]float64{2, 4, 8})), []float64{2, 3, 5.5})
+	checkIteratorEqual(t, EMA[int](1)(FromSlice([]int{2, 4, 8})), []float64{2, 4, 8})
+	assert.Panics(t, func() { EMA[int](0) })
+}
+
+func TestRollingMinMax(t *testing.T) {
+	items := []int{4, 2, 12, 3, 8, 8, 1, 5}
+	checkIteratorEqual(t, RollingMin[int](3)(FromSlice(items)), []int{2, 2, 3, 3, 1, 1})
+	checkIteratorEqual(t, RollingMax[int](3)(FromSlice(items)), []int{12, 12, 12, 8, 8, 8})
+	checkIteratorEqual(t, RollingMax[int](1)(FromSlice(items)), items)
+}
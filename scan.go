@@ -0,0 +1,48 @@
+package iterator
+
+import (
+	"golang.org/x/exp/constraints"
+)
+
+// Scan returns a modifier that folds the items like Fold but emits every intermediate value
+func Scan[T any, S any](start S, fn func(int, T, S) (S, error)) Modifier[T, S] {
+	return func(iter Iterator[T]) Iterator[S] {
+		acc := start
+		return Map(func(i int, item T) (S, error) {
+			var err error
+			acc, err = fn(i, item, acc)
+			return acc, err
+		})(iter)
+	}
+}
+
+// CumulativeSum is a modifier that emits the running total of the numbers
+func CumulativeSum[T constraints.Float | constraints.Integer | constraints.Complex](iter Iterator[T]) Iterator[T] {
+	return Scan(T(0), func(_ int, item T, total T) (T, error) {
+		return total + item, nil
+	})(iter)
+}
+
+// CumulativeProduct is a modifier that emits the running product of the numbers
+func CumulativeProduct[T constraints.Float | constraints.Integer | constraints.Complex](iter Iterator[T]) Iterator[T] {
+	return Scan(T(1), func(_ int, item T, product T) (T, error) {
+		return product * item, nil
+	})(iter)
+}
+
+// Pairwise is a modifier that emits each item paired with the one following it
+func Pairwise[T any](iter Iterator[T]) Iterator[Tuple2[T, T]] {
+	var prev T
+	return FilterMap(func(i int, item T) (Tuple2[T, T], bool, error) {
+		pair := Tuple2[T, T]{First: prev, Second: item}
+		prev = item
+		return pair, i > 0, nil
+	})(iter)
+}
+
+// Diff is a modifier that emits the difference between each number and the one before it
+func Diff[T constraints.Float | constraints.Integer | constraints.Complex](iter Iterator[T]) Iterator[T] {
+	return Map(func(_ int, pair Tuple2[T, T]) (T, error) {
+		return pair.Second - pair.First, nil
+	})(Pairwise(iter))
+}
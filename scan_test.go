@@ -0,0 +1,40 @@
+package iterator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScan(t *testing.T) {
+	concat := Scan("", func(_ int, item string, acc string) (string, error) {
+		return acc + item, nil
+	})
+	checkIteratorEqual(t, concat(FromSlice([]string{"a", "b", "c"})), []string{"a", "ab", "abc"})
+	checkIteratorEqual(t, concat(Empty[string]()), []string{})
+
+	scanErr := errors.New("scan error")
+	_, err := ToSlice(Scan(0, func(i int, item int, acc int) (int, error) {
+		if i == 1 {
+			return 0, scanErr
+		}
+		return acc + item, nil
+	})(Range(1, 3, 1)))
+	assert.ErrorIs(t, err, scanErr)
+}
+
+func TestCumulative(t *testing.T) {
+	checkIteratorEqual(t, CumulativeSum(Range(1, 5, 1)), []int{1, 3, 6, 10, 15})
+	checkIteratorEqual(t, CumulativeProduct(Range(1, 5, 1)), []int{1, 2, 6, 24, 120})
+}
+
+func TestPairwise(t *testing.T) {
+	checkIteratorEqual(t, Pairwise(Range(1, 4, 1)), []Tuple2[int, int]{{1, 2}, {2, 3}, {3, 4}})
+	checkIteratorEqual(t, Pairwise(Once(1)), []Tuple2[int, int]{})
+}
+
+func TestDiff(t *testing.T) {
+	checkIteratorEqual(t, Diff(FromSlice([]int{1, 4, 9, 16})), []int{3, 5, 7})
+	checkIteratorEqual(t, Diff(FromSlice([]float64{1.5, 1})), []float64{-0.5})
+}
@@ -0,0 +1,149 @@
+package iterator
+
+import (
+	"errors"
+	"iter"
+)
+
+// ToSeq returns a range-over-func sequence that consumes all items in the iterator.
+// The iterator is closed when the sequence is exhausted or the loop is stopped early.
+// The returned function reports the first error encountered and should be checked
+// once ranging is done.
+func ToSeq[T any](iterator Iterator[T]) (iter.Seq[T], func() error) {
+	var err error
+	return func(yield func(T) bool) {
+			err = pull(iterator, func(_ int, item T) bool {
+				return yield(item)
+			})
+		}, func() error {
+			return err
+		}
+}
+
+// ToSeq2 returns a range-over-func sequence that consumes all items in the iterator
+// yielding each item with its error.
+// An error from Get, Err or Close is yielded as the last pair of the sequence.
+func ToSeq2[T any](iterator Iterator[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		stopped := false
+		err := pull(iterator, func(_ int, item T) bool {
+			stopped = !yield(item, nil)
+			return !stopped
+		})
+		if err != nil && !stopped {
+			yield(*new(T), err)
+		}
+	}
+}
+
+// ToIndexedSeq returns a range-over-func sequence that consumes all items in the iterator
+// yielding each item with its index.
+// The returned function reports the first error encountered and should be checked
+// once ranging is done.
+func ToIndexedSeq[T any](iterator Iterator[T]) (iter.Seq2[int, T], func() error) {
+	var err error
+	return func(yield func(int, T) bool) {
+			err = pull(iterator, yield)
+		}, func() error {
+			return err
+		}
+}
+
+// pull feeds all items in the iterator to yield until it returns false.
+// Unlike Iterate it closes the iterator on every path, including errors,
+// in which case the error from closing it is joined to the returned one.
+func pull[T any](iterator Iterator[T], yield func(int, T) bool) error {
+	for i := 0; iterator.Next(); i++ {
+		item, err := iterator.Get()
+		if err != nil {
+			return closeAfter(iterator, err)
+		}
+		if !yield(i, item) {
+			return iterator.Close()
+		}
+	}
+
+	if err := iterator.Err(); err != nil {
+		return closeAfter(iterator, err)
+	}
+
+	return iterator.Close()
+}
+
+// closeAfter closes the iterator after err stopped it, keeping both errors
+func closeAfter[T any](iterator Iterator[T], err error) error {
+	if closeErr := iterator.Close(); closeErr != nil {
+		return errors.Join(err, closeErr)
+	}
+	return err
+}
+
+// FromSeq returns an iterator wrapping a range-over-func sequence.
+// Closing the iterator stops the sequence.
+func FromSeq[T any](seq iter.Seq[T]) Iterator[T] {
+	next, stop := iter.Pull(seq)
+	return FromPull(next, stop)
+}
+
+// FromSeq2 returns an iterator wrapping a range-over-func sequence of items and errors.
+// The iterator stops at the first non-nil error and reports it.
+// Closing the iterator stops the sequence.
+func FromSeq2[T any](seq iter.Seq2[T, error]) Iterator[T] {
+	next, stop := iter.Pull2(seq)
+	return &pullIterator[T]{
+		next: next,
+		stop: stop,
+	}
+}
+
+// FromPull returns an iterator wrapping a pull function and its stop function
+// such as the ones returned by iter.Pull.
+func FromPull[T any](next func() (T, bool), stop func()) Iterator[T] {
+	return &pullIterator[T]{
+		next: func() (T, error, bool) {
+			item, ok := next()
+			return item, nil, ok
+		},
+		stop: stop,
+	}
+}
+
+type pullIterator[T any] struct {
+	next func() (T, error, bool)
+	stop func()
+
+	value  T
+	err    error
+	done   bool
+	closed bool
+}
+
+func (iter *pullIterator[T]) Next() bool {
+	if iter.done || iter.closed {
+		return false
+	}
+
+	var ok bool
+	iter.value, iter.err, ok = iter.next()
+	if !ok || iter.err != nil {
+		iter.value = *new(T)
+		iter.done = true
+		return false
+	}
+
+	return true
+}
+
+func (iter *pullIterator[T]) Get() (T, error) { return iter.value, iter.err }
+func (iter *pullIterator[T]) Err() error      { return iter.err }
+
+func (iter *pullIterator[T]) Close() error {
+	if iter.closed {
+		return nil
+	}
+	iter.closed = true
+	if iter.stop != nil {
+		iter.stop()
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package iterator
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSeq(t *testing.T) {
+	seq, errFn := ToSeq(Range(1, 5, 1))
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, slices.Collect(seq))
+	require.NoError(t, errFn())
+
+	var closed bool
+	iter := OnClose(Range(1, 5, 1), func() error {
+		closed = true
+		return nil
+	})
+	seq, errFn = ToSeq(iter)
+	var items []int
+	for item := range seq {
+		if item == 3 {
+			break
+		}
+		items = append(items, item)
+	}
+	assert.Equal(t, []int{1, 2}, items)
+	assert.True(t, closed)
+	require.NoError(t, errFn())
+}
+
+func TestToSeq2(t *testing.T) {
+	errTest := errors.New("test error")
+	iter := Map(func(_ int, item int) (int, error) {
+		if item == 3 {
+			return 0, errTest
+		}
+		return item, nil
+	})(Range(1, 5, 1))
+
+	var items []int
+	var err error
+	for item, itemErr := range ToSeq2(iter) {
+		if itemErr != nil {
+			err = itemErr
+			break
+		}
+		items = append(items, item)
+	}
+	assert.Equal(t, []int{1, 2}, items)
+	require.ErrorIs(t, err, errTest)
+
+	errClose := errors.New("close error")
+	failing := OnClose(FromFunc(func() (int, bool, error) { return 0, false, errTest }), func() error { return errClose })
+	seq, seqErr := ToSeq(failing)
+	for range seq {
+	}
+	assert.ErrorIs(t, seqErr(), errTest)
+	assert.ErrorIs(t, seqErr(), errClose)
+}
+
+func TestToIndexedSeq(t *testing.T) {
+	seq, errFn := ToIndexedSeq(FromSlice([]string{"a", "b", "c"}))
+	m := make(map[int]string)
+	for i, item := range seq {
+		m[i] = item
+	}
+	require.NoError(t, errFn())
+	assert.Equal(t, map[int]string{0: "a", 1: "b", 2: "c"}, m)
+}
+
+func TestFromSeq(t *testing.T) {
+	checkIteratorEqual(t, FromSeq(slices.Values([]int{1, 2, 3})), []int{1, 2, 3})
+
+	var stopped bool
+	iter := FromSeq(func(yield func(int) bool) {
+		defer func() { stopped = true }()
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	})
+	checkIteratorEqual(t, Limit[int](3)(iter), []int{0, 1, 2})
+	assert.True(t, stopped)
+}
+
+func TestFromSeq2(t *testing.T) {
+	errTest := errors.New("test error")
+	iter := FromSeq2(func(yield func(int, error) bool) {
+		if !yield(1, nil) {
+			return
+		}
+		yield(0, errTest)
+	})
+
+	_, err := ToSlice(iter)
+	require.ErrorIs(t, err, errTest)
+}
+
+func TestFromPull(t *testing.T) {
+	next, stop := createPull([]int{1, 2, 3})
+	checkIteratorEqual(t, FromPull(next, stop), []int{1, 2, 3})
+}
+
+func createPull[T any](items []T) (func() (T, bool), func()) {
+	var curr int
+	return func() (T, bool) {
+		if curr >= len(items) {
+			return *new(T), false
+		}
+		curr++
+		return items[curr-1], true
+	}, func() {}
+}
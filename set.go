@@ -0,0 +1,386 @@
+package iterator
+
+type setOp int
+
+const (
+	setUnion setOp = iota
+	setIntersect
+	setExcept
+	setSymmetricDifference
+)
+
+// keep returns how many copies of an item to emit given how many times it occurs in each iterator
+func (op setOp) keep(counts []int) int {
+	if len(counts) == 0 {
+		return 0
+	}
+	least, most, rest := counts[0], counts[0], 0
+	for _, count := range counts[1:] {
+		least, most, rest = min(least, count), max(most, count), rest+count
+	}
+
+	switch op {
+	case setUnion:
+		return most
+	case setIntersect:
+		return least
+	case setExcept:
+		return max(0, counts[0]-rest)
+	default:
+		return most - least
+	}
+}
+
+type setConfig struct {
+	multiset bool
+}
+
+// SetOption configures the set operations
+type SetOption func(*setConfig)
+
+// Multiset makes set operations count duplicate items instead of collapsing them.
+// An item is then emitted as many times as it occurs in the union (most occurrences),
+// intersection (fewest occurrences), difference (occurrences in the first iterator
+// minus those in the others) or symmetric difference (most minus fewest occurrences).
+func Multiset() SetOption {
+	return func(c *setConfig) {
+		c.multiset = true
+	}
+}
+
+func newSetConfig(opts []SetOption) *setConfig {
+	c := &setConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func identityKey[T any](_ int, item T) (T, error) {
+	return item, nil
+}
+
+// Union returns an iterator over the items found in any of iters, each only once.
+// Items are emitted as soon as they are read.
+func Union[T comparable](iters []Iterator[T], opts ...SetOption) Iterator[T] {
+	return UnionFunc(identityKey[T], iters, opts...)
+}
+
+// UnionFunc is like Union but compares items by the key returned by fn.
+func UnionFunc[T any, K comparable](fn func(int, T) (K, error), iters []Iterator[T], opts ...SetOption) Iterator[T] {
+	return hashSetOp(fn, iters, setUnion, newSetConfig(opts))
+}
+
+// UnionSorted is like Union but for iterators sorted according to less, which are read in constant memory.
+func UnionSorted[T any](less func(a, b T) bool, iters []Iterator[T], opts ...SetOption) Iterator[T] {
+	return sortedSetOp(less, iters, setUnion, newSetConfig(opts))
+}
+
+// Intersect returns an iterator over the items of the first iterator found in all the others.
+// All but the first iterator are read ahead and their keys held in memory.
+func Intersect[T comparable](iters []Iterator[T], opts ...SetOption) Iterator[T] {
+	return IntersectFunc(identityKey[T], iters, opts...)
+}
+
+// IntersectFunc is like Intersect but compares items by the key returned by fn.
+func IntersectFunc[T any, K comparable](fn func(int, T) (K, error), iters []Iterator[T], opts ...SetOption) Iterator[T] {
+	return hashSetOp(fn, iters, setIntersect, newSetConfig(opts))
+}
+
+// IntersectSorted is like Intersect but for iterators sorted according to less, which are read in constant memory.
+func IntersectSorted[T any](less func(a, b T) bool, iters []Iterator[T], opts ...SetOption) Iterator[T] {
+	return sortedSetOp(less, iters, setIntersect, newSetConfig(opts))
+}
+
+// Except returns an iterator over the items of the first iterator not found in any of the others.
+// All but the first iterator are read ahead and their keys held in memory.
+func Except[T comparable](iters []Iterator[T], opts ...SetOption) Iterator[T] {
+	return ExceptFunc(identityKey[T], iters, opts...)
+}
+
+// ExceptFunc is like Except but compares items by the key returned by fn.
+func ExceptFunc[T any, K comparable](fn func(int, T) (K, error), iters []Iterator[T], opts ...SetOption) Iterator[T] {
+	return hashSetOp(fn, iters, setExcept, newSetConfig(opts))
+}
+
+// ExceptSorted is like Except but for iterators sorted according to less, which are read in constant memory.
+func ExceptSorted[T any](less func(a, b T) bool, iters []Iterator[T], opts ...SetOption) Iterator[T] {
+	return sortedSetOp(less, iters, setExcept, newSetConfig(opts))
+}
+
+// SymmetricDifference returns an iterator over the items found in some but not all of iters.
+// All iterators are read before the first item is emitted.
+func SymmetricDifference[T comparable](iters []Iterator[T], opts ...SetOption) Iterator[T] {
+	return SymmetricDifferenceFunc(identityKey[T], iters, opts...)
+}
+
+// SymmetricDifferenceFunc is like SymmetricDifference but compares items by the key returned by fn.
+func SymmetricDifferenceFunc[T any, K comparable](fn func(int, T) (K, error), iters []Iterator[T], opts ...SetOption) Iterator[T] {
+	return hashSetOp(fn, iters, setSymmetricDifference, newSetConfig(opts))
+}
+
+// SymmetricDifferenceSorted is like SymmetricDifference but for iterators sorted according to less,
+// which are read in constant memory.
+func SymmetricDifferenceSorted[T any](less func(a, b T) bool, iters []Iterator[T], opts ...SetOption) Iterator[T] {
+	return sortedSetOp(less, iters, setSymmetricDifference, newSetConfig(opts))
+}
+
+func closeAll[T any](iters []Iterator[T]) func() error {
+	return func() error {
+		var err error
+		for i := range iters {
+			if closeErr := iters[i].Close(); err == nil && closeErr != nil {
+				err = closeErr
+			}
+		}
+		return err
+	}
+}
+
+// countKeys reads iter fully and returns how many times each key occurs in it
+func countKeys[T any, K comparable](fn func(int, T) (K, error), iter Iterator[T], multiset bool) (map[K]int, error) {
+	counts := make(map[K]int)
+	err := drain(iter, func(i int, item T) error {
+		key, err := fn(i, item)
+		if err != nil {
+			return err
+		}
+		if multiset || counts[key] == 0 {
+			counts[key]++
+		}
+		return nil
+	})
+	return counts, err
+}
+
+func hashSetOp[T any, K comparable](fn func(int, T) (K, error), iters []Iterator[T], op setOp, config *setConfig) Iterator[T] {
+	switch {
+	case len(iters) == 0:
+		return Empty[T]()
+	case op == setSymmetricDifference:
+		return hashSymmetricDifference(fn, iters, config)
+	case op == setUnion:
+		return hashUnion(fn, iters, config)
+	}
+
+	var limits map[K]int
+	var loaded bool
+	seen := make(map[K]int)
+	index := 0
+
+	return OnClose(FromFunc(func() (T, bool, error) {
+		if !loaded {
+			loaded = true
+			for i, iter := range iters[1:] {
+				counts, err := countKeys(fn, iter, config.multiset)
+				if err != nil {
+					return *new(T), false, err
+				}
+				switch {
+				case i == 0:
+					limits = counts
+				case op == setExcept:
+					for key, count := range counts {
+						limits[key] += count
+					}
+				default:
+					for key, limit := range limits {
+						limits[key] = min(limit, counts[key])
+					}
+				}
+			}
+		}
+
+		for iters[0].Next() {
+			item, err := iters[0].Get()
+			if err != nil {
+				return *new(T), false, err
+			}
+			key, err := fn(index, item)
+			if err != nil {
+				return *new(T), false, err
+			}
+			index++
+
+			seen[key]++
+			occurrence := seen[key]
+			if !config.multiset && occurrence > 1 {
+				continue
+			}
+			if len(iters) == 1 {
+				return item, true, nil
+			}
+
+			limit := limits[key]
+			if op == setIntersect && occurrence <= limit || op == setExcept && occurrence > limit {
+				return item, true, nil
+			}
+		}
+		return *new(T), false, iters[0].Err()
+	}), closeAll(iters))
+}
+
+func hashUnion[T any, K comparable](fn func(int, T) (K, error), iters []Iterator[T], config *setConfig) Iterator[T] {
+	emitted := make(map[K]int)
+	counts := make(map[K]int)
+	current, index := 0, 0
+
+	return OnClose(FromFunc(func() (T, bool, error) {
+		for current < len(iters) {
+			iter := iters[current]
+			if !iter.Next() {
+				if err := iter.Err(); err != nil {
+					return *new(T), false, err
+				}
+				current, index = current+1, 0
+				clear(counts)
+				continue
+			}
+
+			item, err := iter.Get()
+			if err != nil {
+				return *new(T), false, err
+			}
+			key, err := fn(index, item)
+			if err != nil {
+				return *new(T), false, err
+			}
+			index++
+
+			if config.multiset {
+				counts[key]++
+			} else {
+				counts[key] = 1
+			}
+			if counts[key] > emitted[key] {
+				emitted[key]++
+				return item, true, nil
+			}
+		}
+		return *new(T), false, nil
+	}), closeAll(iters))
+}
+
+func hashSymmetricDifference[T any, K comparable](fn func(int, T) (K, error), iters []Iterator[T], config *setConfig) Iterator[T] {
+	type entry struct {
+		item   T
+		counts []int
+	}
+
+	var entries []*entry
+	var loaded bool
+	var pending int
+
+	return OnClose(FromFunc(func() (T, bool, error) {
+		if !loaded {
+			loaded = true
+			keys := make(map[K]*entry)
+			for i, iter := range iters {
+				if err := drain(iter, func(index int, item T) error {
+					key, err := fn(index, item)
+					if err != nil {
+						return err
+					}
+					e, ok := keys[key]
+					if !ok {
+						e = &entry{item: item, counts: make([]int, len(iters))}
+						keys[key] = e
+						entries = append(entries, e)
+					}
+					if config.multiset || e.counts[i] == 0 {
+						e.counts[i]++
+					}
+					return nil
+				}); err != nil {
+					return *new(T), false, err
+				}
+			}
+		}
+
+		for pending == 0 {
+			if len(entries) == 0 {
+				return *new(T), false, nil
+			}
+			pending = setSymmetricDifference.keep(entries[0].counts)
+			if pending == 0 {
+				entries = entries[1:]
+			}
+		}
+
+		item := entries[0].item
+		pending--
+		if pending == 0 {
+			entries = entries[1:]
+		}
+		return item, true, nil
+	}), closeAll(iters))
+}
+
+// sortedSetOp walks iters in step, counting the run of equal items at the head of each.
+// Equal items are represented by the first one read.
+func sortedSetOp[T any](less func(a, b T) bool, iters []Iterator[T], op setOp, config *setConfig) Iterator[T] {
+	heads := make([]T, len(iters))
+	has := make([]bool, len(iters))
+	counts := make([]int, len(iters))
+	var started bool
+	var item T
+	var pending int
+
+	advance := func(i int) error {
+		prev, hadPrev := heads[i], has[i]
+		has[i] = iters[i].Next()
+		if !has[i] {
+			heads[i] = *new(T)
+			return iters[i].Err()
+		}
+		head, err := iters[i].Get()
+		if err != nil {
+			return err
+		}
+		if hadPrev && less(head, prev) {
+			return ErrNotSorted
+		}
+		heads[i] = head
+		return nil
+	}
+
+	return OnClose(FromFunc(func() (T, bool, error) {
+		if !started {
+			started = true
+			for i := range iters {
+				if err := advance(i); err != nil {
+					return *new(T), false, err
+				}
+			}
+		}
+
+		for pending == 0 {
+			smallest := -1
+			for i := range iters {
+				if has[i] && (smallest < 0 || less(heads[i], heads[smallest])) {
+					smallest = i
+				}
+			}
+			if smallest < 0 {
+				return *new(T), false, nil
+			}
+
+			item = heads[smallest]
+			for i := range iters {
+				counts[i] = 0
+				for has[i] && !less(item, heads[i]) {
+					if config.multiset || counts[i] == 0 {
+						counts[i]++
+					}
+					if err := advance(i); err != nil {
+						return *new(T), false, err
+					}
+				}
+			}
+			pending = op.keep(counts)
+		}
+
+		pending--
+		return item, true, nil
+	}), closeAll(iters))
+}
@@ -0,0 +1,105 @@
+package iterator
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setInputs(sorted bool) []Iterator[int] {
+	if sorted {
+		return []Iterator[int]{
+			FromSlice([]int{1, 1, 2, 3, 3, 3}),
+			FromSlice([]int{1, 3, 3, 4}),
+			FromSlice([]int{1, 1, 3, 5}),
+		}
+	}
+	return []Iterator[int]{
+		FromSlice([]int{3, 1, 2, 3, 1, 3}),
+		FromSlice([]int{4, 3, 1, 3}),
+		FromSlice([]int{5, 1, 3, 1}),
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	cases := []struct {
+		name     string
+		hash     func([]Iterator[int], ...SetOption) Iterator[int]
+		sorted   func(func(a, b int) bool, []Iterator[int], ...SetOption) Iterator[int]
+		set      []int
+		multiset []int
+	}{
+		{"union", Union[int], UnionSorted[int], []int{1, 2, 3, 4, 5}, []int{1, 1, 2, 3, 3, 3, 4, 5}},
+		{"intersect", Intersect[int], IntersectSorted[int], []int{1, 3}, []int{1, 3}},
+		{"except", Except[int], ExceptSorted[int], []int{2}, []int{2}},
+		{"symmetric difference", SymmetricDifference[int], SymmetricDifferenceSorted[int], []int{2, 4, 5}, []int{1, 2, 3, 3, 4, 5}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			checkIteratorEqualUnordered(t, c.hash(setInputs(false)), c.set)
+			checkIteratorEqualUnordered(t, c.hash(setInputs(false), Multiset()), c.multiset)
+			checkIteratorEqual(t, c.sorted(less, setInputs(true)), c.set)
+			checkIteratorEqual(t, c.sorted(less, setInputs(true), Multiset()), c.multiset)
+			checkIteratorEqual(t, c.hash(nil), []int{})
+			checkIteratorEqual(t, c.sorted(less, nil), []int{})
+		})
+	}
+}
+
+func TestSetOperationsOrder(t *testing.T) {
+	checkIteratorEqual(t, Union([]Iterator[int]{FromSlice([]int{3, 1, 3}), FromSlice([]int{2, 1})}), []int{3, 1, 2})
+	checkIteratorEqual(t, Intersect([]Iterator[int]{FromSlice([]int{3, 1, 2, 3}), FromSlice([]int{2, 3})}), []int{3, 2})
+	checkIteratorEqual(t, Except([]Iterator[int]{FromSlice([]int{3, 1, 2, 3, 1}), FromSlice([]int{3}), FromSlice([]int{2})}, Multiset()), []int{1, 3, 1})
+	checkIteratorEqual(t, Except([]Iterator[int]{FromSlice([]int{3, 1})}), []int{3, 1})
+}
+
+func TestSetOperationsFunc(t *testing.T) {
+	lower := func(_ int, item string) (string, error) { return strings.ToLower(item), nil }
+
+	checkIteratorEqual(t, UnionFunc(lower, []Iterator[string]{FromSlice([]string{"a", "B"}), FromSlice([]string{"b", "C"})}), []string{"a", "B", "C"})
+	checkIteratorEqual(t, IntersectFunc(lower, []Iterator[string]{FromSlice([]string{"a", "B"}), FromSlice([]string{"b", "C"})}), []string{"B"})
+	checkIteratorEqual(t, ExceptFunc(lower, []Iterator[string]{FromSlice([]string{"a", "B"}), FromSlice([]string{"b", "C"})}), []string{"a"})
+	checkIteratorEqual(t, SymmetricDifferenceFunc(lower, []Iterator[string]{FromSlice([]string{"a", "B"}), FromSlice([]string{"b", "C"})}), []string{"a", "C"})
+
+	keyErr := errors.New("key error")
+	failing := func(i int, item string) (string, error) {
+		if i == 1 {
+			return "", keyErr
+		}
+		return item, nil
+	}
+	_, err := ToSlice(ExceptFunc(failing, []Iterator[string]{FromSlice([]string{"a"}), FromSlice([]string{"b", "c"})}))
+	assert.ErrorIs(t, err, keyErr)
+}
+
+func TestSetOperationsNotSorted(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	_, err := ToSlice(UnionSorted(less, []Iterator[int]{FromSlice([]int{1, 3, 2})}))
+	assert.ErrorIs(t, err, ErrNotSorted)
+}
+
+func TestSetOperationsClose(t *testing.T) {
+	var closed atomic.Int32
+	iters := []Iterator[int]{
+		closeTracker(FromSlice([]int{1, 2}), &closed),
+		closeTracker(FromSlice([]int{2, 3}), &closed),
+	}
+	union := Union(iters)
+	require.True(t, union.Next())
+	require.NoError(t, union.Close())
+	assert.Equal(t, int32(2), closed.Load())
+
+	closed.Store(0)
+	iters = []Iterator[int]{
+		closeTracker(FromSlice([]int{1, 2}), &closed),
+		closeTracker(FromSlice([]int{2, 3}), &closed),
+	}
+	checkIteratorEqual(t, Intersect(iters), []int{2})
+	assert.Equal(t, int32(2), closed.Load())
+}
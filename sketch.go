@@ -0,0 +1,216 @@
+package iterator
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// QuantileSketch estimates quantiles of a stream of numbers in bounded memory using a t-digest.
+// Higher compression keeps more centroids and gives more accurate estimates.
+// Sketches built from separate iterators can be combined with Merge.
+type QuantileSketch struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min         float64
+	max         float64
+}
+
+// NewQuantileSketch returns an empty sketch, compression is usually 100
+func NewQuantileSketch(compression float64) *QuantileSketch {
+	if compression < 1 {
+		panic("NewQuantileSketch: compression cannot be less than one")
+	}
+	return &QuantileSketch{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// SketchQuantiles adds all numbers in the iterator to a new sketch
+func SketchQuantiles[T constraints.Float | constraints.Integer](iter Iterator[T], compression float64) (*QuantileSketch, error) {
+	sketch := NewQuantileSketch(compression)
+	_, err := Iterate(iter, func(_ int, item T) (bool, error) {
+		sketch.Add(float64(item))
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sketch, nil
+}
+
+// Add adds a number to the sketch, NaN is ignored
+func (s *QuantileSketch) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	s.buffer = append(s.buffer, centroid{mean: x, weight: 1})
+	s.count++
+	s.min, s.max = min(s.min, x), max(s.max, x)
+	if len(s.buffer) >= int(5*s.compression) {
+		s.compress()
+	}
+}
+
+// Merge adds all numbers summarised by other to the sketch
+func (s *QuantileSketch) Merge(other *QuantileSketch) {
+	if other.count == 0 {
+		return
+	}
+	s.buffer = append(s.buffer, other.centroids...)
+	s.buffer = append(s.buffer, other.buffer...)
+	s.count += other.count
+	s.min, s.max = min(s.min, other.min), max(s.max, other.max)
+	s.compress()
+}
+
+// Count returns how many numbers were added to the sketch
+func (s *QuantileSketch) Count() int {
+	return int(s.count)
+}
+
+// Quantile returns the estimated value below which the fraction q of the numbers fall.
+// It returns NaN if the sketch is empty.
+func (s *QuantileSketch) Quantile(q float64) float64 {
+	s.compress()
+	if len(s.centroids) == 0 {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return s.min
+	}
+	if q >= 1 {
+		return s.max
+	}
+
+	index := q * s.count
+	// the center of each centroid lies halfway through its weight,
+	// between centers values are interpolated linearly
+	prevValue, prevPos := s.min, 0.0
+	var cumulative float64
+	for _, c := range s.centroids {
+		pos := cumulative + c.weight/2
+		if index < pos {
+			return interpolate(prevValue, c.mean, prevPos, pos, index)
+		}
+		prevValue, prevPos = c.mean, pos
+		cumulative += c.weight
+	}
+	return interpolate(prevValue, s.max, prevPos, s.count, index)
+}
+
+func interpolate(from, to, fromPos, toPos, pos float64) float64 {
+	if toPos <= fromPos {
+		return to
+	}
+	return from + (to-from)*(pos-fromPos)/(toPos-fromPos)
+}
+
+// scale maps a quantile onto the t-digest k1 scale, centroids may span at most one unit of it
+func (s *QuantileSketch) scale(q float64) float64 {
+	return s.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+func (s *QuantileSketch) unscale(k float64) float64 {
+	if k >= s.compression/4 {
+		return 1
+	}
+	return (math.Sin(k*2*math.Pi/s.compression) + 1) / 2
+}
+
+func (s *QuantileSketch) compress() {
+	if len(s.buffer) == 0 {
+		return
+	}
+
+	all := append(s.buffer, s.centroids...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	merged := make([]centroid, 0, len(s.centroids)+1)
+	curr := all[0]
+	var before float64
+	limit := s.unscale(s.scale(0) + 1)
+	for _, c := range all[1:] {
+		if (before+curr.weight+c.weight)/s.count <= limit {
+			curr.weight += c.weight
+			curr.mean += (c.mean - curr.mean) * c.weight / curr.weight
+			continue
+		}
+		merged = append(merged, curr)
+		before += curr.weight
+		limit = s.unscale(s.scale(before/s.count) + 1)
+		curr = c
+	}
+	merged = append(merged, curr)
+
+	s.centroids = merged
+	s.buffer = s.buffer[:0]
+}
+
+// BucketCounts holds the counts of a histogram.
+// Counts[i] is the number of items greater than Bounds[i-1] and at most Bounds[i],
+// the last count holds the items greater than all bounds.
+type BucketCounts struct {
+	Bounds []float64
+	Counts []int
+	Total  int
+}
+
+// LinearBuckets returns count bounds starting at start, each width apart
+func LinearBuckets(start, width float64, count int) []float64 {
+	if count < 1 || width <= 0 {
+		panic("LinearBuckets: count and width must be greater than zero")
+	}
+	bounds := make([]float64, count)
+	for i := range bounds {
+		bounds[i] = start + width*float64(i)
+	}
+	return bounds
+}
+
+// ExponentialBuckets returns count bounds starting at start, each factor times the previous
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	if count < 1 || start <= 0 || factor <= 1 {
+		panic("ExponentialBuckets: count and start must be greater than zero and factor greater than one")
+	}
+	bounds := make([]float64, count)
+	for i := range bounds {
+		bounds[i] = start
+		start *= factor
+	}
+	return bounds
+}
+
+// Histogram counts the numbers in the iterator into buckets with the given upper bounds,
+// which must be strictly increasing.
+func Histogram[T constraints.Float | constraints.Integer](iter Iterator[T], bounds []float64) (BucketCounts, error) {
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] <= bounds[i-1] {
+			panic("Histogram: bounds must be strictly increasing")
+		}
+	}
+
+	counts := BucketCounts{
+		Bounds: bounds,
+		Counts: make([]int, len(bounds)+1),
+	}
+	_, err := Iterate(iter, func(_ int, item T) (bool, error) {
+		counts.Counts[sort.SearchFloat64s(bounds, float64(item))]++
+		counts.Total++
+		return true, nil
+	})
+	if err != nil {
+		return BucketCounts{}, err
+	}
+	return counts, nil
+}
@@ -0,0 +1,76 @@
+package iterator
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func shuffledFloats(n int, seed int64) []float64 {
+	items := make([]float64, n)
+	for i := range items {
+		items[i] = float64(i) / float64(n)
+	}
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+	return items
+}
+
+func TestQuantileSketch(t *testing.T) {
+	sketch, err := SketchQuantiles(FromSlice(shuffledFloats(100000, 1)), 100)
+	require.NoError(t, err)
+	assert.Equal(t, 100000, sketch.Count())
+	assert.InDelta(t, 0.5, sketch.Quantile(0.5), 0.01)
+	assert.InDelta(t, 0.99, sketch.Quantile(0.99), 0.002)
+	assert.InDelta(t, 0.001, sketch.Quantile(0.001), 0.001)
+	assert.Equal(t, 0.0, sketch.Quantile(0))
+	assert.Equal(t, 0.99999, sketch.Quantile(1))
+	assert.Less(t, len(sketch.centroids), 1000)
+}
+
+func TestQuantileSketchMerge(t *testing.T) {
+	items := shuffledFloats(50000, 2)
+	first, err := SketchQuantiles(FromSlice(items[:20000]), 100)
+	require.NoError(t, err)
+	second, err := SketchQuantiles(FromSlice(items[20000:]), 100)
+	require.NoError(t, err)
+
+	first.Merge(second)
+	assert.Equal(t, 50000, first.Count())
+	assert.InDelta(t, 0.5, first.Quantile(0.5), 0.01)
+	assert.InDelta(t, 0.99, first.Quantile(0.99), 0.002)
+}
+
+func TestQuantileSketchSmall(t *testing.T) {
+	sketch := NewQuantileSketch(100)
+	assert.True(t, math.IsNaN(sketch.Quantile(0.5)))
+
+	sketch.Add(7)
+	assert.Equal(t, 7.0, sketch.Quantile(0.5))
+
+	sketch, err := SketchQuantiles(FromSlice([]int{1, 2, 3, 4, 5}), 100)
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, sketch.Quantile(0.5))
+	assert.Equal(t, 1.0, sketch.Quantile(0))
+	assert.Equal(t, 5.0, sketch.Quantile(1))
+}
+
+func TestHistogram(t *testing.T) {
+	counts, err := Histogram(FromSlice([]float64{0.5, 1, 1.5, 2, 10, -3}), LinearBuckets(1, 1, 2))
+	require.NoError(t, err)
+	assert.Equal(t, BucketCounts{
+		Bounds: []float64{1, 2},
+		Counts: []int{3, 2, 1},
+		Total:  6,
+	}, counts)
+
+	counts, err = Histogram(FromSlice([]int{1, 3, 9, 50, 200}), ExponentialBuckets(1, 10, 3))
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1, 10, 100}, counts.Bounds)
+	assert.Equal(t, []int{1, 2, 1, 1}, counts.Counts)
+
+	assert.Panics(t, func() { Histogram(Empty[int](), []float64{2, 1}) })
+}
@@ -0,0 +1,339 @@
+package iterator
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Sort returns a modifier that sorts the items in ascending order.
+// Items are sorted in memory unless SortSpillToDisk is given, in which case sorted runs
+// of up to its threshold items are written to temporary files and merged lazily.
+func Sort[T constraints.Ordered](opts ...SortOption[T]) Modifier[T, T] {
+	return SortFunc(func(a, b T) bool { return a < b }, opts...)
+}
+
+// SortFunc returns a modifier like Sort that orders the items using less.
+func SortFunc[T any](less func(a, b T) bool, opts ...SortOption[T]) Modifier[T, T] {
+	return sortFunc(less, false, opts)
+}
+
+// SortStable returns a modifier like SortFunc that keeps the original order of equal items.
+func SortStable[T any](less func(a, b T) bool, opts ...SortOption[T]) Modifier[T, T] {
+	return sortFunc(less, true, opts)
+}
+
+type sortConfig[T any] struct {
+	threshold int
+	dir       string
+	codec     Codec[T]
+	maxRuns   int
+}
+
+// SortOption configures how the sort modifiers spill items to disk
+type SortOption[T any] func(*sortConfig[T])
+
+// SortSpillToDisk makes the sort keep up to threshold items in memory and write
+// each sorted run of threshold items to a temporary file in dir.
+// An empty dir uses the default directory for temporary files.
+func SortSpillToDisk[T any](threshold int, dir string) SortOption[T] {
+	return func(c *sortConfig[T]) {
+		c.threshold = threshold
+		c.dir = dir
+	}
+}
+
+// SortSpillCodec sets the codec used to write runs to disk, GobCodec by default
+func SortSpillCodec[T any](codec Codec[T]) SortOption[T] {
+	return func(c *sortConfig[T]) {
+		c.codec = codec
+	}
+}
+
+// SortMaxOpenRuns limits how many run files are merged at once, 64 by default.
+// More runs are merged over several passes, each one writing fewer and longer runs.
+func SortMaxOpenRuns[T any](n int) SortOption[T] {
+	if n < 2 {
+		panic("SortMaxOpenRuns: n cannot be less than two")
+	}
+	return func(c *sortConfig[T]) {
+		c.maxRuns = n
+	}
+}
+
+func sortFunc[T any](less func(a, b T) bool, stable bool, opts []SortOption[T]) Modifier[T, T] {
+	config := &sortConfig[T]{codec: GobCodec[T](), maxRuns: 64}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return func(iter Iterator[T]) Iterator[T] {
+		return &sortIterator[T]{
+			source: iter,
+			less:   less,
+			stable: stable,
+			config: config,
+		}
+	}
+}
+
+type sortIterator[T any] struct {
+	source Iterator[T]
+	less   func(a, b T) bool
+	stable bool
+	config *sortConfig[T]
+
+	runs   []*sortRun[T]
+	merged Iterator[T]
+
+	sourceClosed bool
+	started      bool
+	err          error
+}
+
+// sortRun iterates over sorted items written to a file,
+// the file is only open while the run is being read
+type sortRun[T any] struct {
+	name  string
+	count int
+	codec Codec[T]
+
+	file *os.File
+	dec  Decoder[T]
+	curr T
+	err  error
+}
+
+func (r *sortRun[T]) Next() bool {
+	if r.err != nil || r.name == "" {
+		return false
+	}
+	if r.count == 0 {
+		r.err = r.Close()
+		return false
+	}
+	if r.file == nil {
+		if r.file, r.err = os.Open(r.name); r.err != nil {
+			return false
+		}
+		r.dec = r.codec.NewDecoder(bufio.NewReader(r.file))
+	}
+
+	r.curr, r.err = r.dec.Decode()
+	if r.err != nil {
+		return false
+	}
+	r.count--
+	return true
+}
+
+func (r *sortRun[T]) Get() (T, error) { return r.curr, r.err }
+func (r *sortRun[T]) Err() error      { return r.err }
+
+func (r *sortRun[T]) Close() error {
+	if r.name == "" {
+		return nil
+	}
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	if removeErr := os.Remove(r.name); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) && err == nil {
+		err = removeErr
+	}
+	r.name = ""
+	return err
+}
+
+func (iter *sortIterator[T]) sortItems(items []T) {
+	if iter.stable {
+		sort.SliceStable(items, func(i, j int) bool { return iter.less(items[i], items[j]) })
+	} else {
+		sort.Slice(items, func(i, j int) bool { return iter.less(items[i], items[j]) })
+	}
+}
+
+// writeRun writes the sorted items to a new run file and closes it
+func (iter *sortIterator[T]) writeRun(items Iterator[T]) (*sortRun[T], error) {
+	file, err := os.CreateTemp(iter.config.dir, "iterator-sort-*")
+	if err != nil {
+		return nil, err
+	}
+	run := &sortRun[T]{name: file.Name(), codec: iter.config.codec}
+
+	writer := bufio.NewWriter(file)
+	enc := iter.config.codec.NewEncoder(writer)
+	err = drain(items, func(_ int, item T) error {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+		run.count++
+		return nil
+	})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		run.Close()
+		return nil, err
+	}
+	return run, nil
+}
+
+// mergeRuns merges groups of up to maxRuns runs into single runs until no more than
+// maxRuns are left. Groups keep the order of the runs, which keeps the sort stable.
+func (iter *sortIterator[T]) mergeRuns() error {
+	for len(iter.runs) > iter.config.maxRuns {
+		var merged []*sortRun[T]
+		for len(iter.runs) > 0 {
+			n := min(iter.config.maxRuns, len(iter.runs))
+			group := make([]Iterator[T], n)
+			for i, run := range iter.runs[:n] {
+				group[i] = run
+			}
+			run, err := iter.writeRun(MergeSorted(iter.less, group...))
+			for _, r := range iter.runs[:n] {
+				r.Close()
+			}
+			iter.runs = iter.runs[n:]
+			if err != nil {
+				iter.runs = append(merged, iter.runs...)
+				return err
+			}
+			merged = append(merged, run)
+		}
+		iter.runs = merged
+	}
+	return nil
+}
+
+func (iter *sortIterator[T]) closeSource() error {
+	if iter.sourceClosed {
+		return nil
+	}
+	iter.sourceClosed = true
+	return iter.source.Close()
+}
+
+// load reads all items from the source and sorts them into runs
+func (iter *sortIterator[T]) load() error {
+	var items []T
+	for iter.source.Next() {
+		item, err := iter.source.Get()
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+
+		if iter.config.threshold > 0 && len(items) == iter.config.threshold {
+			iter.sortItems(items)
+			run, err := iter.writeRun(FromSlice(items))
+			if err != nil {
+				return err
+			}
+			iter.runs = append(iter.runs, run)
+			items = items[:0]
+		}
+	}
+	if err := iter.source.Err(); err != nil {
+		return err
+	}
+	if err := iter.closeSource(); err != nil {
+		return err
+	}
+
+	iter.sortItems(items)
+	if len(iter.runs) == 0 {
+		iter.merged = FromSlice(items)
+		return nil
+	}
+	if err := iter.mergeRuns(); err != nil {
+		return err
+	}
+
+	// equal items are taken from earlier runs first, which keeps the sort
+	// stable since runs follow the source order
+	runs := make([]Iterator[T], 0, len(iter.runs)+1)
+	for _, run := range iter.runs {
+		runs = append(runs, run)
+	}
+	runs = append(runs, FromSlice(items))
+	iter.merged = MergeSorted(iter.less, runs...)
+	return nil
+}
+
+func (iter *sortIterator[T]) Next() bool {
+	if iter.err != nil {
+		return false
+	}
+	if !iter.started {
+		iter.started = true
+		if iter.err = iter.load(); iter.err != nil {
+			// remove the runs already written, nothing will read them
+			iter.Close()
+			return false
+		}
+	}
+
+	if !iter.merged.Next() {
+		iter.err = iter.merged.Err()
+		return false
+	}
+	return true
+}
+
+func (iter *sortIterator[T]) Get() (T, error) {
+	if iter.err != nil {
+		return *new(T), iter.err
+	}
+	return iter.merged.Get()
+}
+
+func (iter *sortIterator[T]) Err() error { return iter.err }
+
+func (iter *sortIterator[T]) Close() error {
+	err := iter.closeSource()
+	for _, run := range iter.runs {
+		if closeErr := run.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	iter.runs = nil
+	return err
+}
+
+type sortHeapItem[T any] struct {
+	item  T
+	index int
+}
+
+type sortHeap[T any] struct {
+	items []sortHeapItem[T]
+	less  func(a, b T) bool
+}
+
+func (h *sortHeap[T]) Len() int { return len(h.items) }
+
+func (h *sortHeap[T]) Less(i, j int) bool {
+	if h.less(h.items[i].item, h.items[j].item) {
+		return true
+	}
+	if h.less(h.items[j].item, h.items[i].item) {
+		return false
+	}
+	return h.items[i].index < h.items[j].index
+}
+
+func (h *sortHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *sortHeap[T]) Push(x any)    { h.items = append(h.items, x.(sortHeapItem[T])) }
+func (h *sortHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
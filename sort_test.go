@@ -0,0 +1,128 @@
+package iterator
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSort(t *testing.T) {
+	cases := []struct {
+		iter     Iterator[int]
+		expected []int
+	}{
+		{Sort[int]()(Empty[int]()), []int{}},
+		{Sort[int]()(FromSlice([]int{3, 1, 2})), []int{1, 2, 3}},
+		{Sort(SortSpillToDisk[int](2, t.TempDir()))(FromSlice([]int{5, 3, 1, 4, 2})), []int{1, 2, 3, 4, 5}},
+		{Sort(SortSpillToDisk[int](5, t.TempDir()))(FromSlice([]int{5, 3, 1, 4, 2})), []int{1, 2, 3, 4, 5}},
+	}
+
+	for i := range cases {
+		checkIteratorEqual(t, cases[i].iter, cases[i].expected)
+	}
+
+	assert.Panics(t, func() { SortMaxOpenRuns[int](1) })
+}
+
+func TestSortFuncSpill(t *testing.T) {
+	dir := t.TempDir()
+	items := rand.New(rand.NewSource(1)).Perm(1000)
+
+	iter := SortFunc(func(a, b int) bool { return a > b }, SortSpillToDisk[int](64, dir))(FromSlice(items))
+
+	expected := append([]int{}, items...)
+	sort.Sort(sort.Reverse(sort.IntSlice(expected)))
+	checkIteratorEqual(t, iter, expected)
+	checkDirEmpty(t, dir)
+}
+
+func TestSortStable(t *testing.T) {
+	type record struct {
+		Key int
+		Seq int
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	items := make([]record, 200)
+	for i := range items {
+		items[i] = record{Key: rnd.Intn(10), Seq: i}
+	}
+	expected := append([]record{}, items...)
+	sort.SliceStable(expected, func(i, j int) bool { return expected[i].Key < expected[j].Key })
+
+	less := func(a, b record) bool { return a.Key < b.Key }
+	checkIteratorEqual(t, SortStable(less)(FromSlice(items)), expected)
+
+	dir := t.TempDir()
+	checkIteratorEqual(t, SortStable(less, SortSpillToDisk[record](7, dir))(FromSlice(items)), expected)
+	checkDirEmpty(t, dir)
+
+	checkIteratorEqual(t, SortStable(less, SortSpillToDisk[record](3, dir), SortMaxOpenRuns[record](2))(FromSlice(items)), expected)
+	checkDirEmpty(t, dir)
+}
+
+func TestSortMaxOpenRuns(t *testing.T) {
+	dir := t.TempDir()
+	items := rand.New(rand.NewSource(2)).Perm(100)
+	iter := Sort(SortSpillToDisk[int](3, dir), SortSpillCodec[int](jsonCodec[int]{}), SortMaxOpenRuns[int](4))(FromSlice(items))
+
+	require.True(t, iter.Next())
+	// 33 runs are merged in two passes down to 9 and then 3 runs
+	assert.Len(t, iter.(*sortIterator[int]).runs, 3)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	sorted := []int{0}
+	for iter.Next() {
+		item, err := iter.Get()
+		require.NoError(t, err)
+		sorted = append(sorted, item)
+	}
+	require.NoError(t, iter.Err())
+	expected, err := ToSlice(Range(0, 99, 1))
+	require.NoError(t, err)
+	assert.Equal(t, expected, sorted)
+	require.NoError(t, iter.Close())
+	checkDirEmpty(t, dir)
+}
+
+func TestSortClose(t *testing.T) {
+	dir := t.TempDir()
+	iter := Sort(SortSpillToDisk[int](2, dir))(FromSlice([]int{9, 8, 7, 6, 5, 4, 3, 2, 1}))
+
+	require.True(t, iter.Next())
+	item, err := iter.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 1, item)
+	require.NoError(t, iter.Close())
+	checkDirEmpty(t, dir)
+}
+
+func TestSortSourceError(t *testing.T) {
+	dir := t.TempDir()
+	failing := errors.New("failing")
+	var closed atomic.Int32
+	source := closeTracker(FromFunc(func() func() (int, bool, error) {
+		i := 0
+		return func() (int, bool, error) {
+			i++
+			if i > 5 {
+				return 0, false, failing
+			}
+			return i, true, nil
+		}
+	}()), &closed)
+	iter := Sort(SortSpillToDisk[int](2, dir))(source)
+
+	assert.False(t, iter.Next())
+	assert.ErrorIs(t, iter.Err(), failing)
+	assert.Equal(t, int32(1), closed.Load())
+	checkDirEmpty(t, dir)
+}
@@ -0,0 +1,238 @@
+package iterator
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/wlMalk/iterator/internal/buffer"
+)
+
+// Queue holds items buffered by an iterator in FIFO order
+type Queue[T any] interface {
+	Push(T) error
+	// Pop removes the oldest item and reports whether one existed
+	Pop() (T, bool, error)
+	Len() int
+	// Close frees the resources held by the queue
+	Close() error
+}
+
+// Encoder writes items to a stream
+type Encoder[T any] interface {
+	Encode(T) error
+}
+
+// Decoder reads items written by the matching Encoder from a stream
+type Decoder[T any] interface {
+	Decode() (T, error)
+}
+
+// Codec creates encoders and decoders used to write items to disk
+type Codec[T any] interface {
+	NewEncoder(io.Writer) Encoder[T]
+	NewDecoder(io.Reader) Decoder[T]
+}
+
+type gobCodec[T any] struct{}
+
+type gobEncoder[T any] struct{ enc *gob.Encoder }
+
+type gobDecoder[T any] struct{ dec *gob.Decoder }
+
+// GobCodec returns a Codec using encoding/gob
+func GobCodec[T any]() Codec[T] { return gobCodec[T]{} }
+
+func (gobCodec[T]) NewEncoder(w io.Writer) Encoder[T] { return gobEncoder[T]{gob.NewEncoder(w)} }
+func (gobCodec[T]) NewDecoder(r io.Reader) Decoder[T] { return gobDecoder[T]{gob.NewDecoder(r)} }
+
+func (e gobEncoder[T]) Encode(item T) error { return e.enc.Encode(&item) }
+
+func (d gobDecoder[T]) Decode() (T, error) {
+	var item T
+	err := d.dec.Decode(&item)
+	return item, err
+}
+
+type bufferConfig[T any] struct {
+	newQueue  func() Queue[T]
+	threshold int
+	dir       string
+	codec     Codec[T]
+}
+
+// BufferOption configures how an iterator holds the items it has read ahead
+type BufferOption[T any] func(*bufferConfig[T])
+
+// BufferQueue makes the iterator hold items in queues created by fn
+func BufferQueue[T any](fn func() Queue[T]) BufferOption[T] {
+	return func(c *bufferConfig[T]) {
+		c.newQueue = fn
+	}
+}
+
+// SpillToDisk makes the iterator keep up to threshold items in memory and
+// write the rest to temporary files in dir.
+// An empty dir uses the default directory for temporary files.
+func SpillToDisk[T any](threshold int, dir string) BufferOption[T] {
+	return func(c *bufferConfig[T]) {
+		c.threshold = threshold
+		c.dir = dir
+	}
+}
+
+// SpillCodec sets the codec used to write spilled items, gob is used by default
+func SpillCodec[T any](codec Codec[T]) BufferOption[T] {
+	return func(c *bufferConfig[T]) {
+		c.codec = codec
+	}
+}
+
+func newBufferConfig[T any](opts []BufferOption[T]) *bufferConfig[T] {
+	c := &bufferConfig[T]{codec: GobCodec[T]()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *bufferConfig[T]) newBuffer() *buffer.Buffer[T] {
+	if c.newQueue != nil {
+		return buffer.NewWithQueue[T](c.newQueue())
+	}
+	if c.threshold > 0 {
+		return buffer.NewWithQueue[T](newSpillQueue(c.threshold, c.dir, c.codec))
+	}
+	return buffer.New[T]()
+}
+
+// spillQueue keeps the oldest items in memory and appends the rest
+// to segment files once the threshold is reached.
+type spillQueue[T any] struct {
+	threshold int
+	dir       string
+	codec     Codec[T]
+
+	memory  []T
+	sealed  []*spillSegment[T]
+	writing *spillSegment[T]
+	reading *spillSegment[T]
+	length  int
+}
+
+type spillSegment[T any] struct {
+	file   *os.File
+	writer *bufio.Writer
+	enc    Encoder[T]
+	dec    Decoder[T]
+	count  int
+}
+
+func newSpillQueue[T any](threshold int, dir string, codec Codec[T]) *spillQueue[T] {
+	return &spillQueue[T]{threshold: threshold, dir: dir, codec: codec}
+}
+
+func (q *spillQueue[T]) onDisk() bool {
+	return q.reading != nil || q.writing != nil || len(q.sealed) > 0
+}
+
+func (q *spillQueue[T]) Push(item T) error {
+	if !q.onDisk() && len(q.memory) < q.threshold {
+		q.memory = append(q.memory, item)
+		q.length++
+		return nil
+	}
+
+	if q.writing == nil {
+		file, err := os.CreateTemp(q.dir, "iterator-spill-*")
+		if err != nil {
+			return err
+		}
+		writer := bufio.NewWriter(file)
+		q.writing = &spillSegment[T]{
+			file:   file,
+			writer: writer,
+			enc:    q.codec.NewEncoder(writer),
+		}
+	}
+
+	if err := q.writing.enc.Encode(item); err != nil {
+		return err
+	}
+	q.writing.count++
+	q.length++
+	return nil
+}
+
+func (q *spillQueue[T]) Pop() (T, bool, error) {
+	if len(q.memory) > 0 {
+		item := q.memory[0]
+		q.memory[0] = *new(T)
+		q.memory = q.memory[1:]
+		q.length--
+		return item, true, nil
+	}
+
+	if q.reading == nil {
+		if len(q.sealed) == 0 && q.writing != nil {
+			if err := q.writing.seal(); err != nil {
+				return *new(T), false, err
+			}
+			q.sealed = append(q.sealed, q.writing)
+			q.writing = nil
+		}
+		if len(q.sealed) == 0 {
+			return *new(T), false, nil
+		}
+		q.reading = q.sealed[0]
+		q.sealed = q.sealed[1:]
+		q.reading.dec = q.codec.NewDecoder(bufio.NewReader(q.reading.file))
+	}
+
+	item, err := q.reading.dec.Decode()
+	if err != nil {
+		return *new(T), false, err
+	}
+	q.reading.count--
+	q.length--
+	if q.reading.count == 0 {
+		err = q.reading.remove()
+		q.reading = nil
+	}
+	return item, true, err
+}
+
+func (q *spillQueue[T]) Len() int { return q.length }
+
+func (q *spillQueue[T]) Close() error {
+	var err error
+	for _, segment := range append(q.sealed, q.writing, q.reading) {
+		if segment == nil {
+			continue
+		}
+		if removeErr := segment.remove(); err == nil {
+			err = removeErr
+		}
+	}
+	q.memory, q.sealed, q.writing, q.reading = nil, nil, nil, nil
+	q.length = 0
+	return err
+}
+
+func (s *spillSegment[T]) seal() error {
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+	_, err := s.file.Seek(0, io.SeekStart)
+	return err
+}
+
+func (s *spillSegment[T]) remove() error {
+	closeErr := s.file.Close()
+	if err := os.Remove(s.file.Name()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return closeErr
+}
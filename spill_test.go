@@ -0,0 +1,179 @@
+package iterator
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonCodec[T any] struct{}
+
+func (jsonCodec[T]) NewEncoder(w io.Writer) Encoder[T] { return jsonEncoder[T]{json.NewEncoder(w)} }
+func (jsonCodec[T]) NewDecoder(r io.Reader) Decoder[T] { return jsonDecoder[T]{json.NewDecoder(r)} }
+
+type jsonEncoder[T any] struct{ enc *json.Encoder }
+
+func (e jsonEncoder[T]) Encode(item T) error { return e.enc.Encode(item) }
+
+type jsonDecoder[T any] struct{ dec *json.Decoder }
+
+func (d jsonDecoder[T]) Decode() (T, error) {
+	var item T
+	err := d.dec.Decode(&item)
+	return item, err
+}
+
+type failingCodec[T any] struct{ jsonCodec[T] }
+
+func (failingCodec[T]) NewDecoder(r io.Reader) Decoder[T] { return failingDecoder[T]{} }
+
+type failingDecoder[T any] struct{}
+
+func (failingDecoder[T]) Decode() (T, error) { return *new(T), errFailingDecode }
+
+var errFailingDecode = errors.New("failing decode")
+
+func checkDirEmpty(t *testing.T, dir string) {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestSpillQueue(t *testing.T) {
+	for _, codec := range []Codec[int]{GobCodec[int](), jsonCodec[int]{}} {
+		dir := t.TempDir()
+		q := newSpillQueue(2, dir, codec)
+
+		var popped []int
+		pop := func(n int) {
+			for i := 0; i < n; i++ {
+				item, ok, err := q.Pop()
+				require.NoError(t, err)
+				require.True(t, ok)
+				popped = append(popped, item)
+			}
+		}
+
+		for i := 0; i < 5; i++ {
+			require.NoError(t, q.Push(i))
+		}
+		assert.Equal(t, 5, q.Len())
+		pop(3)
+		for i := 5; i < 8; i++ {
+			require.NoError(t, q.Push(i))
+		}
+		pop(5)
+
+		_, ok, err := q.Pop()
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7}, popped)
+
+		require.NoError(t, q.Push(8))
+		require.NoError(t, q.Push(9))
+		require.NoError(t, q.Push(10))
+		require.NoError(t, q.Close())
+		checkDirEmpty(t, dir)
+	}
+}
+
+func TestGroupFuncSpill(t *testing.T) {
+	dir := t.TempDir()
+	iter := Range(0, 99, 1)
+	grouped := GroupFunc(func(_ int, item int) (int, error) { return item % 3, nil }, SpillToDisk[int](4, dir))(iter)
+
+	expected := make([]int, 0, 100)
+	for r := 0; r < 3; r++ {
+		for i := r; i < 100; i += 3 {
+			expected = append(expected, i)
+		}
+	}
+	checkIteratorEqual(t, Flatten(grouped), expected)
+	checkDirEmpty(t, dir)
+
+	dir = t.TempDir()
+	grouped = GroupFunc(func(_ int, item int) (int, error) { return item, nil }, SpillToDisk[int](1, dir))(FromSlice([]int{1, 2, 2, 2, 3, 3, 3, 1}))
+	assert.True(t, grouped.Next())
+	group, err := grouped.Get()
+	assert.Nil(t, err)
+	items, err := ToSlice(group)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 1}, items)
+	assert.Nil(t, grouped.Close())
+	checkDirEmpty(t, dir)
+
+	dir = t.TempDir()
+	failing := errors.New("failing")
+	source := &atomic.Int32{}
+	grouped = GroupFunc(func(_ int, item int) (int, error) {
+		if item == 4 {
+			return 0, failing
+		}
+		return item, nil
+	}, SpillToDisk[int](1, dir))(closeTracker(FromSlice([]int{1, 2, 2, 3, 3, 4}), source))
+	assert.True(t, grouped.Next())
+	group, err = grouped.Get()
+	assert.Nil(t, err)
+	_, err = ToSlice(group)
+	assert.ErrorIs(t, err, failing)
+	grouped.Close()
+	assert.Equal(t, int32(1), source.Load())
+	checkDirEmpty(t, dir)
+}
+
+func TestMirrorSpill(t *testing.T) {
+	dir := t.TempDir()
+	mirrors := Mirror(Range(1, 50, 1), 2, SpillToDisk[int](5, dir), SpillCodec[int](jsonCodec[int]{}))
+
+	expected, err := ToSlice(Range(1, 50, 1))
+	require.NoError(t, err)
+
+	checkIteratorEqual(t, mirrors[0], expected)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		checkIteratorEqual(t, mirrors[1], expected)
+	}()
+	wg.Wait()
+	checkDirEmpty(t, dir)
+}
+
+func TestMirrorSpillError(t *testing.T) {
+	dir := t.TempDir()
+	var closed atomic.Int32
+	mirrors := Mirror(closeTracker(Range(1, 10, 1), &closed), 2, SpillToDisk[int](2, dir), SpillCodec[int](failingCodec[int]{}))
+
+	for i := 1; i <= 5; i++ {
+		require.True(t, mirrors[0].Next())
+		item, err := mirrors[0].Get()
+		require.NoError(t, err)
+		assert.Equal(t, i, item)
+	}
+
+	for mirrors[1].Next() {
+	}
+	assert.ErrorIs(t, mirrors[1].Err(), errFailingDecode)
+	mirrors[0].Close()
+	mirrors[1].Close()
+	assert.Equal(t, int32(1), closed.Load())
+	checkDirEmpty(t, dir)
+}
+
+func TestUniquesSpill(t *testing.T) {
+	dir := t.TempDir()
+	iter := Uniques(FromSlice([]int{1, 1, 2, 1, 3, 4, 3, 1, 1}), SpillToDisk[int](1, dir))
+	checkIteratorEqual(t, iter, []int{2, 4})
+
+	iter = Duplicates(FromSlice([]int{1, 1, 2, 1, 3, 4, 3, 1, 1}), BufferQueue(func() Queue[int] {
+		return newSpillQueue(1, dir, GobCodec[int]())
+	}))
+	checkIteratorEqual(t, iter, []int{1, 3})
+}
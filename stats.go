@@ -0,0 +1,157 @@
+package iterator
+
+import (
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+// extremeBy returns the index and item with the smallest key, or the largest if largest is set.
+// Ties are resolved in favour of the first item.
+func extremeBy[T any, K constraints.Ordered](iter Iterator[T], fn func(int, T) (K, error), largest bool) (int, T, error) {
+	index := -1
+	var item T
+	var key K
+
+	_, err := Iterate(iter, func(i int, curr T) (bool, error) {
+		currKey, err := fn(i, curr)
+		if err != nil {
+			return false, err
+		}
+		if index < 0 || (!largest && currKey < key) || (largest && currKey > key) {
+			index, item, key = i, curr, currKey
+		}
+		return true, nil
+	})
+	if err != nil {
+		return -1, *new(T), err
+	}
+	if index < 0 {
+		return -1, *new(T), ErrNoItems
+	}
+
+	return index, item, nil
+}
+
+// Min returns the smallest item or ErrNoItems if the iterator is empty
+func Min[T constraints.Ordered](iter Iterator[T]) (T, error) {
+	_, item, err := extremeBy(iter, identityKey[T], false)
+	return item, err
+}
+
+// Max returns the largest item or ErrNoItems if the iterator is empty
+func Max[T constraints.Ordered](iter Iterator[T]) (T, error) {
+	_, item, err := extremeBy(iter, identityKey[T], true)
+	return item, err
+}
+
+// MinBy returns the item with the smallest key returned by fn
+func MinBy[T any, K constraints.Ordered](iter Iterator[T], fn func(int, T) (K, error)) (T, error) {
+	_, item, err := extremeBy(iter, fn, false)
+	return item, err
+}
+
+// MaxBy returns the item with the largest key returned by fn
+func MaxBy[T any, K constraints.Ordered](iter Iterator[T], fn func(int, T) (K, error)) (T, error) {
+	_, item, err := extremeBy(iter, fn, true)
+	return item, err
+}
+
+// ArgMin returns the index of the smallest item
+func ArgMin[T constraints.Ordered](iter Iterator[T]) (int, error) {
+	index, _, err := extremeBy(iter, identityKey[T], false)
+	return index, err
+}
+
+// ArgMax returns the index of the largest item
+func ArgMax[T constraints.Ordered](iter Iterator[T]) (int, error) {
+	index, _, err := extremeBy(iter, identityKey[T], true)
+	return index, err
+}
+
+// KahanSum sums all numbers in the iterator compensating for floating point rounding errors
+func KahanSum[T constraints.Float](iter Iterator[T]) (T, error) {
+	var sum, compensation T
+	_, err := Iterate(iter, func(_ int, item T) (bool, error) {
+		y := item - compensation
+		t := sum + y
+		compensation = (t - sum) - y
+		sum = t
+		return true, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sum, nil
+}
+
+// Stats summarises the numbers of an iterator
+type Stats struct {
+	Count int
+	Sum   float64
+	Min   float64
+	Max   float64
+	Mean  float64
+	// Variance is the population variance
+	Variance float64
+}
+
+// StdDev returns the population standard deviation
+func (s Stats) StdDev() float64 {
+	return math.Sqrt(s.Variance)
+}
+
+// SampleVariance returns the variance of the numbers as a sample of a larger population
+func (s Stats) SampleVariance() float64 {
+	if s.Count < 2 {
+		return 0
+	}
+	return s.Variance * float64(s.Count) / float64(s.Count-1)
+}
+
+// Statistics computes Stats in a single pass using Welford's algorithm,
+// Sum is compensated for floating point rounding errors like in KahanSum
+func Statistics[T constraints.Float | constraints.Integer](iter Iterator[T]) (Stats, error) {
+	var stats Stats
+	var m2, compensation float64
+
+	_, err := Iterate(iter, func(_ int, item T) (bool, error) {
+		x := float64(item)
+		stats.Count++
+		if stats.Count == 1 {
+			stats.Min, stats.Max = x, x
+		} else {
+			stats.Min, stats.Max = min(stats.Min, x), max(stats.Max, x)
+		}
+		y := x - compensation
+		t := stats.Sum + y
+		compensation = (t - stats.Sum) - y
+		stats.Sum = t
+
+		delta := x - stats.Mean
+		stats.Mean += delta / float64(stats.Count)
+		m2 += delta * (x - stats.Mean)
+		return true, nil
+	})
+	if err != nil {
+		return Stats{}, err
+	}
+	if stats.Count == 0 {
+		return Stats{}, ErrNoItems
+	}
+
+	stats.Variance = m2 / float64(stats.Count)
+	return stats, nil
+}
+
+// Mean returns the arithmetic mean of the numbers in the iterator
+func Mean[T constraints.Float | constraints.Integer](iter Iterator[T]) (float64, error) {
+	stats, err := Statistics(iter)
+	return stats.Mean, err
+}
+
+// Variance returns the population variance of the numbers in the iterator
+func Variance[T constraints.Float | constraints.Integer](iter Iterator[T]) (float64, error) {
+	stats, err := Statistics(iter)
+	return stats.Variance, err
+}
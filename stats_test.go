@@ -0,0 +1,97 @@
+package iterator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinMax(t *testing.T) {
+	minimum, err := Min(FromSlice([]int{3, 1, 4, 1, 5}))
+	require.NoError(t, err)
+	assert.Equal(t, 1, minimum)
+
+	maximum, err := Max(FromSlice([]int{3, 1, 4, 1, 5}))
+	require.NoError(t, err)
+	assert.Equal(t, 5, maximum)
+
+	index, err := ArgMin(FromSlice([]int{3, 1, 4, 1, 5}))
+	require.NoError(t, err)
+	assert.Equal(t, 1, index)
+
+	index, err = ArgMax(FromSlice([]int{5, 1, 5}))
+	require.NoError(t, err)
+	assert.Equal(t, 0, index)
+
+	length := func(_ int, item string) (int, error) { return len(item), nil }
+	shortest, err := MinBy(FromSlice([]string{"ccc", "a", "bb", "d"}), length)
+	require.NoError(t, err)
+	assert.Equal(t, "a", shortest)
+
+	longest, err := MaxBy(FromSlice([]string{"ccc", "a", "bb", "eee"}), length)
+	require.NoError(t, err)
+	assert.Equal(t, "ccc", longest)
+}
+
+func TestMinMaxErrors(t *testing.T) {
+	_, err := Min(Empty[int]())
+	assert.ErrorIs(t, err, ErrNoItems)
+
+	_, err = ArgMax(Empty[int]())
+	assert.ErrorIs(t, err, ErrNoItems)
+
+	keyErr := errors.New("key error")
+	_, err = MinBy(FromSlice([]int{1, 2}), func(_ int, _ int) (int, error) { return 0, keyErr })
+	assert.ErrorIs(t, err, keyErr)
+}
+
+func TestKahanSum(t *testing.T) {
+	items := make([]float64, 0, 10001)
+	items = append(items, 1)
+	for i := 0; i < 10000; i++ {
+		items = append(items, 1e-16)
+	}
+
+	sum, err := KahanSum(FromSlice(items))
+	require.NoError(t, err)
+	assert.InDelta(t, 1+1e-12, sum, 1e-15)
+
+	naive, err := Sum(FromSlice(items))
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, naive)
+}
+
+func TestStatistics(t *testing.T) {
+	stats, err := Statistics(FromSlice([]int{2, 4, 4, 4, 5, 5, 7, 9}))
+	require.NoError(t, err)
+	assert.Equal(t, 8, stats.Count)
+	assert.Equal(t, 40.0, stats.Sum)
+	assert.Equal(t, 2.0, stats.Min)
+	assert.Equal(t, 9.0, stats.Max)
+	assert.InDelta(t, 5.0, stats.Mean, 1e-12)
+	assert.InDelta(t, 4.0, stats.Variance, 1e-12)
+	assert.InDelta(t, 2.0, stats.StdDev(), 1e-12)
+	assert.InDelta(t, 32.0/7, stats.SampleVariance(), 1e-12)
+
+	mean, err := Mean(FromSlice([]float64{1, 2}))
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, mean)
+
+	variance, err := Variance(FromSlice([]float64{1, 3}))
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, variance)
+
+	_, err = Statistics(Empty[float64]())
+	assert.ErrorIs(t, err, ErrNoItems)
+
+	// a plain running sum drops every 1e-16 added to 1
+	items := []float64{1}
+	for i := 0; i < 10; i++ {
+		items = append(items, 1e-16)
+	}
+	stats, err = Statistics(FromSlice(items))
+	require.NoError(t, err)
+	assert.Equal(t, 1+1e-15, stats.Sum)
+}
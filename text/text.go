@@ -0,0 +1,167 @@
+package text
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"unicode/utf8"
+
+	it "github.com/wlMalk/iterator"
+)
+
+var bom = []byte{0xEF, 0xBB, 0xBF}
+
+// Reader is an iterator over the tokens of a text stream read with a bufio.Scanner
+type Reader[T any] struct {
+	reader  io.Reader
+	closer  func() error
+	split   bufio.SplitFunc
+	convert func([]byte) T
+
+	scanner      *bufio.Scanner
+	maxTokenSize int
+	skipBOM      bool
+	finished     bool
+
+	curr T
+	err  error
+}
+
+// MaxTokenSize sets the size of the longest token the reader accepts, bufio.MaxScanTokenSize by default
+func (r *Reader[T]) MaxTokenSize(size int) { r.maxTokenSize = size }
+
+// SkipBOM makes the reader drop a UTF-8 byte order mark at the start of the stream
+func (r *Reader[T]) SkipBOM(skip bool) { r.skipBOM = skip }
+
+func (r *Reader[T]) start() {
+	src := r.reader
+	if r.skipBOM {
+		br := bufio.NewReader(src)
+		if prefix, _ := br.Peek(len(bom)); bytes.Equal(prefix, bom) {
+			br.Discard(len(bom))
+		}
+		src = br
+	}
+
+	r.scanner = bufio.NewScanner(src)
+	r.scanner.Split(r.split)
+	if r.maxTokenSize > 0 {
+		r.scanner.Buffer(make([]byte, 0, min(r.maxTokenSize, 4096)), r.maxTokenSize)
+	}
+}
+
+func (r *Reader[T]) Next() bool {
+	if r.finished || r.err != nil {
+		return false
+	}
+	if r.scanner == nil {
+		r.start()
+	}
+
+	if !r.scanner.Scan() {
+		r.finished = true
+		r.err = r.scanner.Err()
+		return false
+	}
+	r.curr = r.convert(r.scanner.Bytes())
+
+	return true
+}
+
+func (r *Reader[T]) Get() (T, error) {
+	return r.curr, r.err
+}
+
+func (r *Reader[T]) Close() error {
+	if r.closer != nil {
+		return r.closer()
+	}
+	return nil
+}
+
+func (r *Reader[T]) Err() error { return r.err }
+
+func newReader[T any](r io.Reader, split bufio.SplitFunc, convert func([]byte) T) *Reader[T] {
+	reader := &Reader[T]{reader: r, split: split, convert: convert}
+	if closer, ok := r.(io.ReadCloser); ok {
+		reader.closer = closer.Close
+	}
+	return reader
+}
+
+func toString(token []byte) string { return string(token) }
+
+// Lines returns a reader over the lines of r, without their \n or \r\n endings
+func Lines(r io.Reader) *Reader[string] {
+	return newReader(r, bufio.ScanLines, toString)
+}
+
+// Words returns a reader over the space separated words of r
+func Words(r io.Reader) *Reader[string] {
+	return newReader(r, bufio.ScanWords, toString)
+}
+
+// Split returns a reader over the parts of r separated by delim
+func Split(r io.Reader, delim string) *Reader[string] {
+	if delim == "" {
+		panic("Split: delim cannot be empty")
+	}
+	sep := []byte(delim)
+	return newReader(r, func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.Index(data, sep); i >= 0 {
+			return i + len(sep), data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}, toString)
+}
+
+// Runes returns a reader over the runes of r, invalid UTF-8 is read as utf8.RuneError
+func Runes(r io.Reader) *Reader[rune] {
+	return newReader(r, bufio.ScanRunes, func(token []byte) rune {
+		char, _ := utf8.DecodeRune(token)
+		return char
+	})
+}
+
+type Writer[T ~string] struct {
+	writer  io.Writer
+	iter    it.Iterator[T]
+	useCRLF bool
+}
+
+func (w *Writer[T]) UseCRLF(useCRLF bool) { w.useCRLF = useCRLF }
+
+func (w *Writer[T]) Write() error {
+	buffered := bufio.NewWriter(w.writer)
+	ending := "\n"
+	if w.useCRLF {
+		ending = "\r\n"
+	}
+
+	_, err := it.Iterate(w.iter, func(_ int, line T) (bool, error) {
+		if _, err := buffered.WriteString(string(line)); err != nil {
+			return false, err
+		}
+		if _, err := buffered.WriteString(ending); err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		w.iter.Close()
+		return err
+	}
+
+	return buffered.Flush()
+}
+
+// WriteLines returns a writer of the items of iter to w, each on its own line
+func WriteLines[T ~string](w io.Writer, iter it.Iterator[T]) *Writer[T] {
+	return &Writer[T]{
+		writer: w,
+		iter:   iter,
+	}
+}
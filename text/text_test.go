@@ -0,0 +1,72 @@
+package text
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wlMalk/iterator"
+	"github.com/wlMalk/iterator/internal/utils"
+)
+
+func checkIteratorEqual[T any](t *testing.T, iter iterator.Iterator[T], items []T) {
+	utils.CheckIteratorEqual[T](t, iter, items)
+}
+
+func TestLines(t *testing.T) {
+	checkIteratorEqual[string](t, Lines(strings.NewReader("a\r\nb\n\nc")), []string{"a", "b", "", "c"})
+	checkIteratorEqual[string](t, Lines(strings.NewReader("")), []string{})
+}
+
+func TestSkipBOM(t *testing.T) {
+	checkIteratorEqual[string](t, Lines(strings.NewReader("\xEF\xBB\xBFa\nb\n")), []string{"\xEF\xBB\xBFa", "b"})
+
+	reader := Lines(strings.NewReader("\xEF\xBB\xBFa\nb\n"))
+	reader.SkipBOM(true)
+	checkIteratorEqual[string](t, reader, []string{"a", "b"})
+
+	runes := Runes(strings.NewReader("\xEF\xBB\xBFab"))
+	runes.SkipBOM(true)
+	checkIteratorEqual[rune](t, runes, []rune{'a', 'b'})
+}
+
+func TestWords(t *testing.T) {
+	checkIteratorEqual[string](t, Words(strings.NewReader("  the quick\tbrown\nfox ")), []string{"the", "quick", "brown", "fox"})
+}
+
+func TestSplit(t *testing.T) {
+	checkIteratorEqual[string](t, Split(strings.NewReader("a;;b;;;c"), ";;"), []string{"a", "b", ";c"})
+	checkIteratorEqual[string](t, Split(strings.NewReader("a,b,"), ","), []string{"a", "b"})
+}
+
+func TestRunes(t *testing.T) {
+	checkIteratorEqual[rune](t, Runes(strings.NewReader("héllo")), []rune{'h', 'é', 'l', 'l', 'o'})
+}
+
+func TestMaxTokenSize(t *testing.T) {
+	reader := Lines(strings.NewReader("short\n" + strings.Repeat("x", 100) + "\n"))
+	reader.MaxTokenSize(16)
+
+	_, err := iterator.ToSlice[string](reader)
+	assert.ErrorIs(t, err, bufio.ErrTooLong)
+}
+
+func TestWriteLines(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteLines(&buf, iterator.FromSlice([]string{"a", "b"})).Write())
+	assert.Equal(t, "a\nb\n", buf.String())
+
+	buf.Reset()
+	writer := WriteLines(&buf, iterator.FromSlice([]string{"a", "b"}))
+	writer.UseCRLF(true)
+	require.NoError(t, writer.Write())
+	assert.Equal(t, "a\r\nb\r\n", buf.String())
+
+	iterErr := errors.New("iterator error")
+	failing := iterator.FromFunc(func() (string, bool, error) { return "", false, iterErr })
+	assert.ErrorIs(t, WriteLines(&buf, failing).Write(), iterErr)
+}
@@ -89,6 +89,13 @@ func (iter *sequenceIterator) Get() (time.Time, error) {
 func (iter *sequenceIterator) Close() error { return nil }
 func (iter *sequenceIterator) Err() error   { return nil }
 
+// Diff is a modifier that emits the duration between each time and the one before it
+func Diff(iter iterator.Iterator[time.Time]) iterator.Iterator[time.Duration] {
+	return iterator.Map(func(_ int, pair iterator.Tuple2[time.Time, time.Time]) (time.Duration, error) {
+		return pair.Second.Sub(pair.First), nil
+	})(iterator.Pairwise(iter))
+}
+
 // DaysInMonth returns an iterator of time.Time for days in the given month of year
 func DaysInMonth(month time.Month, year int, loc *time.Location) iterator.Iterator[time.Time] {
 	if month < time.January || month > time.December {
@@ -61,6 +61,15 @@ func TestRange(t *testing.T) {
 	}
 }
 
+func TestDiff(t *testing.T) {
+	checkIteratorEqual(t, Diff(iterator.FromSlice([]time.Time{day(1), day(2), day(4)})), []time.Duration{
+		24 * time.Hour, 48 * time.Hour,
+	})
+	checkIteratorEqual(t, Diff(Range(day(1), day(1).Add(3*time.Hour), time.Hour)), []time.Duration{
+		time.Hour, time.Hour, time.Hour,
+	})
+}
+
 func TestDaysInMonth(t *testing.T) {
 	checkIteratorEqual(t, DaysInMonth(1, 2023, time.UTC), []time.Time{
 		day(1), day(2), day(3), day(4), day(5), day(6), day(7), day(8), day(9), day(10),
@@ -0,0 +1,353 @@
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BatchByTime returns a modifier that splits the iterator into batches of up to maxSize items,
+// emitting a batch early once maxWait has passed since its first item was read.
+// The source is read in a separate goroutine. Closing the result does not wait for a pending Next on the source,
+// the source is closed once that Next returns instead.
+func BatchByTime[T any](maxSize int, maxWait time.Duration) Modifier[T, Iterator[T]] {
+	if maxSize < 1 || maxWait <= 0 {
+		panic("BatchByTime: maxSize and maxWait must be greater than zero")
+	}
+	return func(iter Iterator[T]) Iterator[Iterator[T]] {
+		var items <-chan ValErr[T]
+		var stop func() error
+		var done bool
+		var err error
+
+		return OnClose(FromFunc(func() (Iterator[T], bool, error) {
+			if items == nil {
+				items, stop = pump(context.Background(), iter)
+			}
+			if done {
+				return nil, false, err
+			}
+
+			first, ok := <-items
+			if !ok || first.Err != nil {
+				done, err = true, first.Err
+				return nil, false, err
+			}
+
+			batch := []T{first.Val}
+			timer := time.NewTimer(maxWait)
+			defer timer.Stop()
+			for len(batch) < maxSize {
+				select {
+				case item, ok := <-items:
+					if !ok || item.Err != nil {
+						// the error is reported after the items read before it
+						done, err = true, item.Err
+						return FromSlice(batch), true, nil
+					}
+					batch = append(batch, item.Val)
+				case <-timer.C:
+					return FromSlice(batch), true, nil
+				}
+			}
+			return FromSlice(batch), true, nil
+		}), func() error {
+			if stop != nil {
+				return stop()
+			}
+			return iter.Close()
+		})
+	}
+}
+
+// pump reads iter in a new goroutine and sends its items and a final error on the returned channel,
+// which is closed once iter ends. stop makes the goroutine exit and closes iter, without waiting
+// for a pending Next on iter, which closes iter once it returns instead.
+func pump[T any](ctx context.Context, iter Iterator[T]) (<-chan ValErr[T], func() error) {
+	ctx, cancel := context.WithCancel(ctx)
+	src := &detachable[T]{iter: iter}
+	items := make(chan ValErr[T])
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		defer close(items)
+		for src.Next() {
+			item, err := src.Get()
+			select {
+			case items <- ValErr[T]{Val: item, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+		if err := src.Err(); err != nil {
+			select {
+			case items <- ValErr[T]{Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return items, func() error {
+		cancel()
+		if !src.detach() {
+			return nil
+		}
+		<-finished
+		return src.Close()
+	}
+}
+
+// LatePolicy decides what happens to items arriving after their window was emitted
+type LatePolicy int
+
+const (
+	// LateDrop skips late items
+	LateDrop LatePolicy = iota
+	// LateFail stops the iterator with a *LateError
+	LateFail
+)
+
+// LateError is returned for late items under LateFail
+type LateError struct {
+	Index     int
+	Timestamp time.Time
+	Watermark time.Time
+}
+
+func (e *LateError) Error() string {
+	return fmt.Sprintf("iterator: item %d at %s arrived after watermark %s", e.Index, e.Timestamp, e.Watermark)
+}
+
+type windowConfig struct {
+	lateness time.Duration
+	policy   LatePolicy
+}
+
+// WindowOption configures the event time windows
+type WindowOption func(*windowConfig)
+
+// AllowedLateness keeps windows open until the latest timestamp seen is d past their end,
+// so items arriving out of order by up to d still make it into their windows.
+func AllowedLateness(d time.Duration) WindowOption {
+	return func(c *windowConfig) {
+		c.lateness = d
+	}
+}
+
+// OnLate sets what happens to items arriving after their window was emitted, LateDrop by default
+func OnLate(policy LatePolicy) WindowOption {
+	return func(c *windowConfig) {
+		c.policy = policy
+	}
+}
+
+// TumblingWindow returns a modifier that groups items into consecutive windows of size
+// by the timestamp fn returns for each of them. Windows are emitted in order once
+// the watermark, the latest timestamp seen minus the allowed lateness, passes their end.
+func TumblingWindow[T any](size time.Duration, fn func(int, T) (time.Time, error), opts ...WindowOption) Modifier[T, Iterator[T]] {
+	if size <= 0 {
+		panic("TumblingWindow: size must be greater than zero")
+	}
+	return SlidingWindow(size, size, fn, opts...)
+}
+
+// SlidingWindow is like TumblingWindow but starts a window every slide,
+// so an item belongs to every window of size covering its timestamp.
+func SlidingWindow[T any](size, slide time.Duration, fn func(int, T) (time.Time, error), opts ...WindowOption) Modifier[T, Iterator[T]] {
+	if size <= 0 || slide <= 0 {
+		panic("SlidingWindow: size and slide must be greater than zero")
+	}
+	return func(iter Iterator[T]) Iterator[Iterator[T]] {
+		return eventWindows(iter, fn, &fixedWindows[T]{size: size, slide: slide}, opts)
+	}
+}
+
+// SessionWindow returns a modifier that groups items whose timestamps are less than gap apart.
+// A session is emitted once the watermark passes its last timestamp by gap.
+func SessionWindow[T any](gap time.Duration, fn func(int, T) (time.Time, error), opts ...WindowOption) Modifier[T, Iterator[T]] {
+	if gap <= 0 {
+		panic("SessionWindow: gap must be greater than zero")
+	}
+	return func(iter Iterator[T]) Iterator[Iterator[T]] {
+		return eventWindows(iter, fn, &sessionWindows[T]{gap: gap}, opts)
+	}
+}
+
+// windowAssigner holds the open windows of an event time windowing
+type windowAssigner[T any] interface {
+	// add puts item in its open windows and reports false if they are all closed
+	add(timestamp, watermark time.Time, item T) bool
+	// flush removes and returns the items of windows closed by watermark, or all if final
+	flush(watermark time.Time, final bool) [][]T
+}
+
+func eventWindows[T any](iter Iterator[T], fn func(int, T) (time.Time, error), windows windowAssigner[T], opts []WindowOption) Iterator[Iterator[T]] {
+	config := &windowConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	var pending [][]T
+	var latest time.Time
+	var count int
+	var finished bool
+
+	return OnClose(FromFunc(func() (Iterator[T], bool, error) {
+		for len(pending) == 0 {
+			if finished {
+				return nil, false, nil
+			}
+			if !iter.Next() {
+				if err := iter.Err(); err != nil {
+					return nil, false, err
+				}
+				finished = true
+				pending = windows.flush(time.Time{}, true)
+				continue
+			}
+
+			item, err := iter.Get()
+			if err != nil {
+				return nil, false, err
+			}
+			timestamp, err := fn(count, item)
+			if err != nil {
+				return nil, false, err
+			}
+			index := count
+			count++
+
+			if index == 0 || timestamp.After(latest) {
+				latest = timestamp
+			}
+			watermark := latest.Add(-config.lateness)
+			if !windows.add(timestamp, watermark, item) && config.policy == LateFail {
+				return nil, false, &LateError{Index: index, Timestamp: timestamp, Watermark: watermark}
+			}
+			pending = windows.flush(watermark, false)
+		}
+
+		window := pending[0]
+		pending = pending[1:]
+		return FromSlice(window), true, nil
+	}), iter.Close)
+}
+
+type fixedWindow[T any] struct {
+	start time.Time
+	items []T
+}
+
+// fixedWindows assigns items to windows of size starting every slide, kept sorted by start
+type fixedWindows[T any] struct {
+	size  time.Duration
+	slide time.Duration
+	open  []*fixedWindow[T]
+}
+
+func (w *fixedWindows[T]) add(timestamp, watermark time.Time, item T) bool {
+	var added bool
+	for start := timestamp.Truncate(w.slide); start.Add(w.size).After(timestamp); start = start.Add(-w.slide) {
+		if !start.Add(w.size).After(watermark) {
+			break
+		}
+
+		i := sort.Search(len(w.open), func(i int) bool { return !w.open[i].start.Before(start) })
+		if i == len(w.open) || !w.open[i].start.Equal(start) {
+			w.open = append(w.open, nil)
+			copy(w.open[i+1:], w.open[i:])
+			w.open[i] = &fixedWindow[T]{start: start}
+		}
+		w.open[i].items = append(w.open[i].items, item)
+		added = true
+	}
+	return added
+}
+
+func (w *fixedWindows[T]) flush(watermark time.Time, final bool) [][]T {
+	var closed [][]T
+	for len(w.open) > 0 && (final || !w.open[0].start.Add(w.size).After(watermark)) {
+		closed = append(closed, w.open[0].items)
+		w.open = w.open[1:]
+	}
+	return closed
+}
+
+type session[T any] struct {
+	start time.Time
+	end   time.Time
+	items []T
+}
+
+// sessionWindows merges items into sessions, kept sorted by start
+type sessionWindows[T any] struct {
+	gap  time.Duration
+	open []*session[T]
+}
+
+func (w *sessionWindows[T]) add(timestamp, watermark time.Time, item T) bool {
+	var merged *session[T]
+	var kept []*session[T]
+	for _, s := range w.open {
+		if timestamp.After(s.start.Add(-w.gap)) && timestamp.Before(s.end.Add(w.gap)) {
+			if merged == nil {
+				merged = s
+				kept = append(kept, s)
+				continue
+			}
+			merged.start, merged.end = minTime(merged.start, s.start), maxTime(merged.end, s.end)
+			merged.items = append(merged.items, s.items...)
+			continue
+		}
+		kept = append(kept, s)
+	}
+
+	if merged == nil {
+		if !timestamp.Add(w.gap).After(watermark) {
+			return false
+		}
+		merged = &session[T]{start: timestamp, end: timestamp}
+		i := sort.Search(len(kept), func(i int) bool { return kept[i].start.After(timestamp) })
+		kept = append(kept, nil)
+		copy(kept[i+1:], kept[i:])
+		kept[i] = merged
+	}
+
+	merged.start, merged.end = minTime(merged.start, timestamp), maxTime(merged.end, timestamp)
+	merged.items = append(merged.items, item)
+	w.open = kept
+	return true
+}
+
+func (w *sessionWindows[T]) flush(watermark time.Time, final bool) [][]T {
+	var closed [][]T
+	var kept []*session[T]
+	for _, s := range w.open {
+		if final || !s.end.Add(w.gap).After(watermark) {
+			closed = append(closed, s.items)
+			continue
+		}
+		kept = append(kept, s)
+	}
+	w.open = kept
+	return closed
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
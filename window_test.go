@@ -0,0 +1,153 @@
+package iterator
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func windowSlices[T any](t *testing.T, iter Iterator[Iterator[T]]) ([][]T, error) {
+	t.Helper()
+	windows := [][]T{}
+	_, err := Iterate(iter, func(_ int, window Iterator[T]) (bool, error) {
+		items, err := ToSlice(window)
+		if err != nil {
+			return false, err
+		}
+		windows = append(windows, items)
+		return true, nil
+	})
+	return windows, err
+}
+
+func TestBatchByTime(t *testing.T) {
+	source := make(chan int)
+	go func() {
+		for _, item := range []int{1, 2, 3} {
+			source <- item
+		}
+		time.Sleep(200 * time.Millisecond)
+		source <- 4
+		source <- 5
+		close(source)
+	}()
+
+	batches, err := windowSlices(t, BatchByTime[int](2, 50*time.Millisecond)(FromChannel(source)))
+	require.NoError(t, err)
+	assert.Equal(t, [][]int{{1, 2}, {3}, {4, 5}}, batches)
+}
+
+func TestBatchByTimeError(t *testing.T) {
+	sourceErr := errors.New("source error")
+	var sent bool
+	source := FromFunc(func() (int, bool, error) {
+		if !sent {
+			sent = true
+			return 1, true, nil
+		}
+		return 0, false, sourceErr
+	})
+
+	batches := BatchByTime[int](10, time.Second)(source)
+	require.True(t, batches.Next())
+	batch, err := batches.Get()
+	require.NoError(t, err)
+	checkIteratorEqual(t, batch, []int{1})
+	assert.False(t, batches.Next())
+	assert.ErrorIs(t, batches.Err(), sourceErr)
+	assert.NoError(t, batches.Close())
+}
+
+func TestBatchByTimeClose(t *testing.T) {
+	batches := BatchByTime[int](2, time.Second)(Ascending(0, 1))
+	require.True(t, batches.Next())
+	assert.NoError(t, batches.Close())
+}
+
+func TestBatchByTimeCloseIdle(t *testing.T) {
+	c := make(chan int, 1)
+	c <- 1
+	var closed atomic.Int32
+	batches := BatchByTime[int](2, 10*time.Millisecond)(closeTracker(FromChannel(c), &closed))
+	require.True(t, batches.Next())
+	batch, err := batches.Get()
+	require.NoError(t, err)
+	checkIteratorEqual(t, batch, []int{1})
+
+	returned := make(chan error)
+	go func() { returned <- batches.Close() }()
+	select {
+	case err := <-returned:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		require.FailNow(t, "Close waited for a source blocked in Next")
+	}
+
+	// the source is closed once its pending Next returns
+	close(c)
+	assert.Eventually(t, func() bool { return closed.Load() == 1 }, time.Second, time.Millisecond)
+}
+
+type event struct {
+	id int
+	at int
+}
+
+func eventTime(_ int, e event) (time.Time, error) {
+	return time.Unix(int64(e.at), 0), nil
+}
+
+func eventIDs(windows [][]event) [][]int {
+	ids := make([][]int, len(windows))
+	for i, window := range windows {
+		ids[i] = []int{}
+		for _, e := range window {
+			ids[i] = append(ids[i], e.id)
+		}
+	}
+	return ids
+}
+
+func TestTumblingWindow(t *testing.T) {
+	events := []event{{1, 0}, {2, 3}, {3, 11}, {4, 9}, {5, 12}, {6, 25}, {7, 14}}
+
+	windows, err := windowSlices(t, TumblingWindow(10*time.Second, eventTime)(FromSlice(events)))
+	require.NoError(t, err)
+	assert.Equal(t, [][]int{{1, 2}, {3, 5}, {6}}, eventIDs(windows))
+
+	windows, err = windowSlices(t, TumblingWindow(10*time.Second, eventTime, AllowedLateness(5*time.Second))(FromSlice(events)))
+	require.NoError(t, err)
+	assert.Equal(t, [][]int{{1, 2, 4}, {3, 5}, {6}}, eventIDs(windows))
+
+	_, err = windowSlices(t, TumblingWindow(10*time.Second, eventTime, OnLate(LateFail))(FromSlice(events)))
+	var lateErr *LateError
+	require.ErrorAs(t, err, &lateErr)
+	assert.Equal(t, 3, lateErr.Index)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	events := []event{{1, 1}, {2, 6}, {3, 12}}
+	windows, err := windowSlices(t, SlidingWindow(10*time.Second, 5*time.Second, eventTime)(FromSlice(events)))
+	require.NoError(t, err)
+	assert.Equal(t, [][]int{{1}, {1, 2}, {2, 3}, {3}}, eventIDs(windows))
+}
+
+func TestSessionWindow(t *testing.T) {
+	events := []event{{1, 0}, {2, 3}, {3, 20}, {4, 6}, {5, 22}, {6, 40}}
+	windows, err := windowSlices(t, SessionWindow(5*time.Second, eventTime, AllowedLateness(15*time.Second))(FromSlice(events)))
+	require.NoError(t, err)
+	assert.Equal(t, [][]int{{1, 2, 4}, {3, 5}, {6}}, eventIDs(windows))
+
+	windows, err = windowSlices(t, SessionWindow(5*time.Second, eventTime)(FromSlice(events)))
+	require.NoError(t, err)
+	assert.Equal(t, [][]int{{1, 2}, {3, 5}, {6}}, eventIDs(windows))
+
+	events = []event{{1, 0}, {2, 8}, {3, 4}}
+	windows, err = windowSlices(t, SessionWindow(5*time.Second, eventTime, AllowedLateness(10*time.Second))(FromSlice(events)))
+	require.NoError(t, err)
+	assert.Equal(t, [][]int{{1, 2, 3}}, eventIDs(windows))
+}
@@ -0,0 +1,163 @@
+package iterator
+
+// Tuple2 holds one item from each of two zipped iterators
+type Tuple2[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Tuple3 holds one item from each of three zipped iterators
+type Tuple3[A any, B any, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+type zipMode int
+
+const (
+	zipShortest zipMode = iota
+	zipLongest
+	zipStrict
+)
+
+// zipSide is the part of an iterator the zip core needs regardless of its item type
+type zipSide interface {
+	Next() bool
+	Err() error
+	Close() error
+}
+
+// zip advances sides in step and builds each item with get,
+// which is told which sides still have items.
+func zip[O any](sides []zipSide, mode zipMode, get func(has []bool) (O, error)) Iterator[O] {
+	has := make([]bool, len(sides))
+	done := make([]bool, len(sides))
+
+	return OnClose(FromFunc(func() (O, bool, error) {
+		if len(sides) == 0 {
+			return *new(O), false, nil
+		}
+
+		some, all := false, true
+		for i, side := range sides {
+			has[i] = !done[i] && side.Next()
+			if !has[i] {
+				if !done[i] {
+					done[i] = true
+					if err := side.Err(); err != nil {
+						return *new(O), false, err
+					}
+				}
+				if mode == zipShortest {
+					return *new(O), false, nil
+				}
+			}
+			some, all = some || has[i], all && has[i]
+		}
+
+		if !some {
+			return *new(O), false, nil
+		}
+		if mode == zipStrict && !all {
+			return *new(O), false, ErrUnequalLen
+		}
+
+		item, err := get(has)
+		if err != nil {
+			return *new(O), false, err
+		}
+		return item, true, nil
+	}), func() error {
+		var err error
+		for _, side := range sides {
+			if closeErr := side.Close(); err == nil && closeErr != nil {
+				err = closeErr
+			}
+		}
+		return err
+	})
+}
+
+// getOr returns the current item of iter if it has one, or def otherwise
+func getOr[T any](iter Iterator[T], has bool, def T) (T, error) {
+	if !has {
+		return def, nil
+	}
+	return iter.Get()
+}
+
+func zip2[A any, B any](a Iterator[A], b Iterator[B], mode zipMode, defA A, defB B) Iterator[Tuple2[A, B]] {
+	return zip([]zipSide{a, b}, mode, func(has []bool) (Tuple2[A, B], error) {
+		first, err := getOr(a, has[0], defA)
+		if err != nil {
+			return Tuple2[A, B]{}, err
+		}
+		second, err := getOr(b, has[1], defB)
+		if err != nil {
+			return Tuple2[A, B]{}, err
+		}
+		return Tuple2[A, B]{First: first, Second: second}, nil
+	})
+}
+
+// Zip2 returns an iterator pairing the items of a and b, it ends when either of them ends
+func Zip2[A any, B any](a Iterator[A], b Iterator[B]) Iterator[Tuple2[A, B]] {
+	return zip2(a, b, zipShortest, *new(A), *new(B))
+}
+
+// Zip3 returns an iterator grouping the items of a, b and c, it ends when any of them ends
+func Zip3[A any, B any, C any](a Iterator[A], b Iterator[B], c Iterator[C]) Iterator[Tuple3[A, B, C]] {
+	return zip([]zipSide{a, b, c}, zipShortest, func(_ []bool) (Tuple3[A, B, C], error) {
+		first, err := a.Get()
+		if err != nil {
+			return Tuple3[A, B, C]{}, err
+		}
+		second, err := b.Get()
+		if err != nil {
+			return Tuple3[A, B, C]{}, err
+		}
+		third, err := c.Get()
+		if err != nil {
+			return Tuple3[A, B, C]{}, err
+		}
+		return Tuple3[A, B, C]{First: first, Second: second, Third: third}, nil
+	})
+}
+
+// ZipN returns an iterator over slices holding one item from each of iters, it ends when any of them ends
+func ZipN[T any](iters ...Iterator[T]) Iterator[[]T] {
+	sides := make([]zipSide, len(iters))
+	for i := range iters {
+		sides[i] = iters[i]
+	}
+
+	return zip(sides, zipShortest, func(_ []bool) ([]T, error) {
+		items := make([]T, len(iters))
+		for i := range iters {
+			item, err := iters[i].Get()
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	})
+}
+
+// ZipLongest is like Zip2 but runs until both a and b end, filling the ended side with its default
+func ZipLongest[A any, B any](a Iterator[A], b Iterator[B], defA A, defB B) Iterator[Tuple2[A, B]] {
+	return zip2(a, b, zipLongest, defA, defB)
+}
+
+// ZipStrict is like Zip2 but returns ErrUnequalLen if one of a and b ends before the other
+func ZipStrict[A any, B any](a Iterator[A], b Iterator[B]) Iterator[Tuple2[A, B]] {
+	return zip2(a, b, zipStrict, *new(A), *new(B))
+}
+
+// Unzip splits a map iterator into synchronized iterators over its keys and values.
+// Items read ahead by one of them are buffered for the other, opts control how.
+func Unzip[K comparable, V any](iter Iterator[KV[K, V]], opts ...BufferOption[KV[K, V]]) (Iterator[K], Iterator[V]) {
+	mirrors := Mirror(iter, 2, opts...)
+	return Keys(mirrors[0]), Values(mirrors[1])
+}
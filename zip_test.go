@@ -0,0 +1,56 @@
+package iterator
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZip(t *testing.T) {
+	checkIteratorEqual(t, Zip2(FromSlice([]int{1, 2, 3}), FromSlice([]string{"a", "b"})), []Tuple2[int, string]{{1, "a"}, {2, "b"}})
+	checkIteratorEqual(t, Zip3(FromSlice([]int{1, 2}), FromSlice([]string{"a", "b"}), FromSlice([]bool{true, false, true})), []Tuple3[int, string, bool]{{1, "a", true}, {2, "b", false}})
+	checkIteratorSliceEqual(t, ZipN(FromSlice([]int{1, 2}), FromSlice([]int{3, 4}), FromSlice([]int{5, 6, 7})), [][]int{{1, 3, 5}, {2, 4, 6}})
+	checkIteratorSliceEqual(t, ZipN[int](), [][]int{})
+}
+
+func TestZipLongest(t *testing.T) {
+	checkIteratorEqual(t, ZipLongest(FromSlice([]int{1, 2, 3}), FromSlice([]string{"a"}), 0, "-"), []Tuple2[int, string]{{1, "a"}, {2, "-"}, {3, "-"}})
+	checkIteratorEqual(t, ZipLongest(Empty[int](), FromSlice([]string{"a"}), -1, "-"), []Tuple2[int, string]{{-1, "a"}})
+}
+
+func TestZipStrict(t *testing.T) {
+	checkIteratorEqual(t, ZipStrict(FromSlice([]int{1, 2}), FromSlice([]string{"a", "b"})), []Tuple2[int, string]{{1, "a"}, {2, "b"}})
+
+	zipped := ZipStrict(FromSlice([]int{1, 2}), FromSlice([]string{"a"}))
+	require.True(t, zipped.Next())
+	item, err := zipped.Get()
+	require.NoError(t, err)
+	assert.Equal(t, Tuple2[int, string]{1, "a"}, item)
+	assert.False(t, zipped.Next())
+	assert.ErrorIs(t, zipped.Err(), ErrUnequalLen)
+}
+
+func TestZipError(t *testing.T) {
+	sourceErr := errors.New("source error")
+	failing := FromFunc(func() (int, bool, error) { return 0, false, sourceErr })
+
+	_, err := ToSlice(ZipLongest(FromSlice([]int{1}), failing, 0, 0))
+	assert.ErrorIs(t, err, sourceErr)
+}
+
+func TestZipClose(t *testing.T) {
+	var closed atomic.Int32
+	zipped := Zip2(closeTracker(FromSlice([]int{1, 2}), &closed), closeTracker(FromSlice([]int{3, 4}), &closed))
+	require.True(t, zipped.Next())
+	require.NoError(t, zipped.Close())
+	assert.Equal(t, int32(2), closed.Load())
+}
+
+func TestUnzip(t *testing.T) {
+	keys, values := Unzip(FromSlice([]KV[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}))
+	checkIteratorEqual(t, values, []int{1, 2, 3})
+	checkIteratorEqual(t, keys, []string{"a", "b", "c"})
+}